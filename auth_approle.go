@@ -4,14 +4,124 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"time"
+	"net/http"
+	"sync"
 
 	hashiVault "github.com/hashicorp/vault/api"
 	"github.com/hashicorp/vault/api/auth/approle"
 )
 
+// ErrWrappingTokenInvalid is returned when a response-wrapped secret ID cannot be unwrapped
+// because its wrapping token has already been used, has expired or does not exist.
+var ErrWrappingTokenInvalid = errors.New("wrapping token is invalid")
+
+// NewAppRoleAuth creates an AuthMethod that logs in with the AppRole auth method.
+func NewAppRoleAuth(roleID, secretID string, opts ...AuthOption) (AuthMethod, error) {
+	if secretID == "" {
+		return nil, errors.New("secretID is empty")
+	}
+
+	return NewAppRoleAuthFromSource(roleID, CredentialFromString(secretID), opts...)
+}
+
+// NewAppRoleAuthFromSource creates an AuthMethod that logs in with the AppRole auth method.
+// The secret ID is read from the source on every login, unless WithWrappedSecretID is set, in which
+// case the source returns a response-wrapping token that is unwrapped once on the first login.
+func NewAppRoleAuthFromSource(roleID string, secretID CredentialSource, opts ...AuthOption) (AuthMethod, error) {
+	if roleID == "" {
+		return nil, errors.New("roleID is empty")
+	} else if secretID == nil {
+		return nil, errors.New("secretID source is nil")
+	}
+
+	o := newAuthOptions(defaultMountAppRole, opts...)
+
+	getSecretID := func(context.Context, *hashiVault.Client) (string, error) {
+		return secretID()
+	}
+	if o.wrappedSecretID {
+		getSecretID = (&wrappedSecretID{wrappingToken: secretID, o: o}).get
+	}
+
+	return &builtinAuth{
+		name:      authNameAppRole,
+		renewable: true,
+		login: func(ctx context.Context, v *hashiVault.Client) (*hashiVault.Secret, error) {
+			id, err := getSecretID(ctx, v)
+			if err != nil {
+				return nil, fmt.Errorf("unable to get AppRole secret ID: %w", err)
+			}
+			return appRoleLogin(ctx, v, roleID, id, o)
+		},
+	}, nil
+}
+
+// wrappedSecretID unwraps a response-wrapped secret ID and keeps it for later logins,
+// as a wrapping token can only be unwrapped once.
+type wrappedSecretID struct {
+	wrappingToken CredentialSource
+	o             *authOptions
+
+	mu       sync.Mutex
+	secretID string
+}
+
+// get returns the unwrapped secret ID, unwrapping it on the first call.
+func (w *wrappedSecretID) get(ctx context.Context, v *hashiVault.Client) (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.secretID != "" {
+		return w.secretID, nil
+	}
+
+	wrappingToken, err := w.wrappingToken()
+	if err != nil {
+		return "", fmt.Errorf("unable to get wrapping token: %w", err)
+	}
+
+	secretID, err := unwrapSecretID(ctx, v, wrappingToken, w.o)
+	if err != nil {
+		return "", err
+	}
+
+	w.secretID = secretID
+	return secretID, nil
+}
+
+// unwrapSecretID unwraps the secret ID held by the given wrapping token.
+func unwrapSecretID(ctx context.Context, client *hashiVault.Client, wrappingToken string, o *authOptions) (string, error) {
+	ctx, cancel := o.loginContext(ctx)
+	defer cancel()
+
+	// Unwrapping authenticates with the wrapping token, which must not be sent with the login that follows.
+	token := client.Token()
+	defer client.SetToken(token)
+	client.ClearToken()
+
+	secret, err := client.Logical().UnwrapWithContext(ctx, wrappingToken)
+	if err != nil {
+		var respErr *hashiVault.ResponseError
+		if errors.As(err, &respErr) &&
+			(respErr.StatusCode == http.StatusBadRequest || respErr.StatusCode == http.StatusForbidden) {
+			return "", fmt.Errorf("%w: %w", ErrWrappingTokenInvalid, err)
+		}
+		return "", fmt.Errorf("unable to unwrap secret ID: %w", err)
+	}
+	if secret == nil {
+		return "", fmt.Errorf("%w: no data was returned after unwrapping", ErrWrappingTokenInvalid)
+	}
+
+	secretID, ok := secret.Data["secret_id"].(string)
+	if !ok || secretID == "" {
+		return "", errors.New("wrapped response does not contain a secret ID")
+	}
+
+	return secretID, nil
+}
+
 // appRoleLogin authenticates with Vault using the AppRole auth method.
-func appRoleLogin(client *hashiVault.Client, roleID, secretID string) (*hashiVault.Secret, error) {
+func appRoleLogin(ctx context.Context, client *hashiVault.Client, roleID, secretID string, o *authOptions) (*hashiVault.Secret, error) {
 	appRoleSecretID := &approle.SecretID{
 		FromString: secretID,
 	}
@@ -20,12 +130,13 @@ func appRoleLogin(client *hashiVault.Client, roleID, secretID string) (*hashiVau
 	appRoleAuth, err := approle.NewAppRoleAuth(
 		roleID,
 		appRoleSecretID,
+		approle.WithMountPath(o.mount),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create AppRole auth: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := o.loginContext(ctx)
 	defer cancel()
 
 	authInfo, err := client.Auth().Login(ctx, appRoleAuth)
@@ -0,0 +1,176 @@
+package vaulty
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAppRoleAuthFromSource(t *testing.T) {
+	secretIDFile := filepath.Join(t.TempDir(), "secret-id")
+	require.NoError(t, os.WriteFile(secretIDFile, []byte("from-file\n"), 0o600))
+	t.Setenv("VAULTY_TEST_APPROLE_SECRET_ID", "from-env")
+
+	tests := []struct {
+		name     string
+		secretID CredentialSource
+		want     string
+	}{
+		{
+			name:     "string",
+			secretID: CredentialFromString("from-string"),
+			want:     "from-string",
+		},
+		{
+			name:     "file",
+			secretID: CredentialFromFile(secretIDFile),
+			want:     "from-file",
+		},
+		{
+			name:     "env",
+			secretID: CredentialFromEnv("VAULTY_TEST_APPROLE_SECRET_ID"),
+			want:     "from-env",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]any
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /v1/auth/approle/login", func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				writeTestJSON(t, w, longLivedAuthResponse("approle-token"))
+			})
+			srv := newTestVaultServer(t, mux)
+
+			vc, err := NewClient(
+				WithLogger(slog.New(slog.DiscardHandler)),
+				WithAddr(srv.URL),
+				WithAppRoleAuthFromSource("role-id", tt.secretID),
+			)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				require.NoError(t, vc.Close(context.Background()))
+			})
+
+			require.Equal(t, "approle-token", vc.Client().Token())
+			require.Equal(t, map[string]any{"role_id": "role-id", "secret_id": tt.want}, body)
+		})
+	}
+}
+
+func TestWithAppRoleAuth_WrappedSecretID(t *testing.T) {
+	t.Parallel()
+
+	var unwraps atomic.Int32
+	var (
+		mu          sync.Mutex
+		secretIDs   []string
+		loginTokens []string
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/sys/wrapping/unwrap", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "wrapping-token", r.Header.Get("X-Vault-Token"))
+		if unwraps.Add(1) > 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			writeTestJSON(t, w, map[string]any{"errors": []string{"wrapping token is not valid or does not exist"}})
+			return
+		}
+		writeTestJSON(t, w, map[string]any{"data": map[string]any{"secret_id": "unwrapped-secret-id"}})
+	})
+	mux.HandleFunc("PUT /v1/auth/approle/login", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			SecretID string `json:"secret_id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		mu.Lock()
+		secretIDs = append(secretIDs, body.SecretID)
+		loginTokens = append(loginTokens, r.Header.Get("X-Vault-Token"))
+		logins := len(secretIDs)
+		mu.Unlock()
+
+		// The first token expires straight away, so the client logs in again.
+		if logins == 1 {
+			writeTestJSON(t, w, expiredAuthResponse("approle-token-1"))
+			return
+		}
+		writeTestJSON(t, w, longLivedAuthResponse("approle-token-2"))
+	})
+	srv := newTestVaultServer(t, mux)
+
+	vc, err := NewClient(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithAppRoleAuth("role-id", "wrapping-token", WithWrappedSecretID()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	require.Eventually(t, func() bool {
+		return vc.Client().Token() == "approle-token-2"
+	}, time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Equal(t, int32(1), unwraps.Load())
+	require.Equal(t, []string{"unwrapped-secret-id", "unwrapped-secret-id"}, secretIDs)
+	require.Equal(t, []string{"", ""}, loginTokens)
+}
+
+func TestWithAppRoleAuth_WrappingTokenInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{
+			name:   "consumed or expired",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "permission denied",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var logins atomic.Int32
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /v1/sys/wrapping/unwrap", func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				writeTestJSON(t, w, map[string]any{"errors": []string{"wrapping token is not valid or does not exist"}})
+			})
+			mux.HandleFunc("PUT /v1/auth/approle/login", func(w http.ResponseWriter, _ *http.Request) {
+				logins.Add(1)
+				writeTestJSON(t, w, longLivedAuthResponse("approle-token"))
+			})
+			srv := newTestVaultServer(t, mux)
+
+			_, err := NewClient(
+				WithLogger(slog.New(slog.DiscardHandler)),
+				WithAddr(srv.URL),
+				WithAppRoleAuth("role-id", "wrapping-token", WithWrappedSecretID()),
+			)
+			require.ErrorIs(t, err, ErrWrappingTokenInvalid)
+			require.Zero(t, logins.Load())
+		})
+	}
+}
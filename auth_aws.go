@@ -0,0 +1,206 @@
+package vaulty
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	hashiVault "github.com/hashicorp/vault/api"
+)
+
+// AWSCredentials are the credentials used to sign the AWS IAM login request.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// AWSCredentialsProvider is a function that returns the AWS credentials to sign the login request with.
+type AWSCredentialsProvider func(ctx context.Context) (*AWSCredentials, error)
+
+// NewAWSIAMAuth creates an AuthMethod that logs in with the AWS auth method.
+// The login request is a locally signed sts:GetCallerIdentity request.
+func NewAWSIAMAuth(role string, opts ...AuthOption) (AuthMethod, error) {
+	if role == "" {
+		return nil, errors.New("role name is empty")
+	}
+
+	o := newAuthOptions(defaultMountAWS, opts...)
+	return &builtinAuth{
+		name:      authNameAWS,
+		renewable: true,
+		login: func(ctx context.Context, v *hashiVault.Client) (*hashiVault.Secret, error) {
+			return awsIAMLogin(ctx, v, role, o)
+		},
+	}, nil
+}
+
+// awsIAMLogin authenticates with Vault using the AWS auth method with a signed sts:GetCallerIdentity request.
+func awsIAMLogin(ctx context.Context, client *hashiVault.Client, role string, o *authOptions) (*hashiVault.Secret, error) {
+	ctx, cancel := o.loginContext(ctx)
+	defer cancel()
+
+	credentialsProvider := o.awsCredentials
+	if credentialsProvider == nil {
+		credentialsProvider = defaultAWSCredentials
+	}
+
+	creds, err := credentialsProvider(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get aws credentials: %w", err)
+	}
+
+	loginData, err := awsIAMLoginData(creds, o.awsRegion, o.awsServerID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("unable to create aws login data: %w", err)
+	}
+	loginData["role"] = role
+
+	authInfo, err := client.Logical().WriteWithContext(ctx, o.loginPath(), loginData)
+	if err != nil {
+		return nil, fmt.Errorf("unable to login to aws auth method: %w", err)
+	}
+	if authInfo == nil {
+		return nil, errors.New("no auth info was returned after login")
+	}
+
+	return authInfo, nil
+}
+
+// awsIAMLoginData signs a sts:GetCallerIdentity request and returns it in the form expected by the AWS auth method.
+func awsIAMLoginData(creds *AWSCredentials, region, serverID string, now time.Time) (map[string]any, error) {
+	if creds == nil || creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, errors.New("aws credentials are incomplete")
+	}
+
+	endpoint := awsSTSGlobalEndpoint
+	if region == "" {
+		region = defaultAWSRegion
+	} else if region != defaultAWSRegion {
+		endpoint = fmt.Sprintf("https://sts.%s.amazonaws.com/", region)
+	}
+
+	body := []byte(awsGetCallerIdentityBody)
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body)) // nolint:noctx // The request is signed, never sent
+	if err != nil {
+		return nil, fmt.Errorf("unable to create sts request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	if serverID != "" {
+		req.Header.Set(awsServerIDHeader, serverID)
+	}
+
+	signAWSRequest(req, body, creds, region, "sts", now)
+
+	headers, err := json.Marshal(req.Header)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal sts request headers: %w", err)
+	}
+
+	return map[string]any{
+		"iam_http_request_method": req.Method,
+		"iam_request_url":         base64.StdEncoding.EncodeToString([]byte(req.URL.String())),
+		"iam_request_body":        base64.StdEncoding.EncodeToString(body),
+		"iam_request_headers":     base64.StdEncoding.EncodeToString(headers),
+	}, nil
+}
+
+// defaultAWSCredentials returns the AWS credentials from the environment, falling back to the shared credentials file.
+func defaultAWSCredentials(context.Context) (*AWSCredentials, error) {
+	if creds := awsCredentialsFromEnv(); creds != nil {
+		return creds, nil
+	}
+
+	creds, err := awsCredentialsFromSharedFile()
+	if err != nil {
+		return nil, fmt.Errorf("no aws credentials in environment or shared credentials file: %w", err)
+	}
+
+	return creds, nil
+}
+
+// awsCredentialsFromEnv returns the AWS credentials set in the standard environment variables, or nil if unset.
+func awsCredentialsFromEnv() *AWSCredentials {
+	creds := &AWSCredentials{
+		AccessKeyID:     os.Getenv(envAWSAccessKeyID),
+		SecretAccessKey: os.Getenv(envAWSSecretAccessKey),
+		SessionToken:    os.Getenv(envAWSSessionToken),
+	}
+
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil
+	}
+
+	return creds
+}
+
+// awsCredentialsFromSharedFile reads the AWS credentials of the current profile from the shared credentials file.
+func awsCredentialsFromSharedFile() (*AWSCredentials, error) {
+	path := os.Getenv(envAWSSharedCredentialsFile)
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("unable to find home directory: %w", err)
+		}
+		path = filepath.Join(home, ".aws", "credentials")
+	}
+
+	profile := os.Getenv(envAWSProfile)
+	if profile == "" {
+		profile = "default"
+	}
+
+	f, err := os.Open(path) // nolint:gosec // The path is the standard AWS credentials location
+	if err != nil {
+		return nil, fmt.Errorf("unable to open shared credentials file: %w", err)
+	}
+	defer f.Close() // nolint:errcheck // Read only
+
+	creds := new(AWSCredentials)
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "", strings.HasPrefix(line, "#"), strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		case section != profile:
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		switch strings.TrimSpace(key) {
+		case "aws_access_key_id":
+			creds.AccessKeyID = strings.TrimSpace(value)
+		case "aws_secret_access_key":
+			creds.SecretAccessKey = strings.TrimSpace(value)
+		case "aws_session_token":
+			creds.SessionToken = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read shared credentials file: %w", err)
+	}
+
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, fmt.Errorf("profile %s not found in shared credentials file", profile)
+	}
+
+	return creds, nil
+}
@@ -0,0 +1,138 @@
+package vaulty
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// decodeTestBase64 decodes a base64 login field.
+func decodeTestBase64(t *testing.T, v any) string {
+	t.Helper()
+
+	s, ok := v.(string)
+	require.True(t, ok)
+
+	b, err := base64.StdEncoding.DecodeString(s)
+	require.NoError(t, err)
+
+	return string(b)
+}
+
+func TestWithAWSIAMAuth(t *testing.T) {
+	t.Parallel()
+
+	var body map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/aws-prod/login", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		writeTestJSON(t, w, longLivedAuthResponse("aws-token"))
+	})
+	srv := newTestVaultServer(t, mux)
+
+	vc, err := NewClient(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithAWSIAMAuth("ec2-app",
+			WithAuthMount("aws-prod"),
+			WithAWSServerID("vault.example.com"),
+			WithAWSCredentialsProvider(func(context.Context) (*AWSCredentials, error) {
+				return &AWSCredentials{
+					AccessKeyID:     "AKIDEXAMPLE",
+					SecretAccessKey: "secret",
+					SessionToken:    "session",
+				}, nil
+			}),
+		),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	require.Equal(t, "aws-token", vc.Client().Token())
+	require.Equal(t, "ec2-app", body["role"])
+	require.Equal(t, http.MethodPost, body["iam_http_request_method"])
+	require.Equal(t, "https://sts.amazonaws.com/", decodeTestBase64(t, body["iam_request_url"]))
+	require.Equal(t, "Action=GetCallerIdentity&Version=2011-06-15", decodeTestBase64(t, body["iam_request_body"]))
+
+	headers := make(http.Header)
+	require.NoError(t, json.Unmarshal([]byte(decodeTestBase64(t, body["iam_request_headers"])), &headers))
+	require.Equal(t, "vault.example.com", headers.Get(awsServerIDHeader))
+	require.Equal(t, "session", headers.Get("X-Amz-Security-Token"))
+	require.NotEmpty(t, headers.Get("X-Amz-Date"))
+	require.Regexp(t,
+		`^AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/\d{8}/us-east-1/sts/aws4_request, `+
+			`SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-vault-aws-iam-server-id, `+
+			`Signature=[0-9a-f]{64}$`,
+		headers.Get("Authorization"),
+	)
+}
+
+func TestAWSIAMLoginData_Region(t *testing.T) {
+	t.Parallel()
+
+	data, err := awsIAMLoginData(&AWSCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+	}, "eu-west-2", "", time.Now())
+	require.NoError(t, err)
+	require.Equal(t, "https://sts.eu-west-2.amazonaws.com/", decodeTestBase64(t, data["iam_request_url"]))
+
+	_, err = awsIAMLoginData(&AWSCredentials{}, "", "", time.Now())
+	require.EqualError(t, err, "aws credentials are incomplete")
+}
+
+func TestDefaultAWSCredentials(t *testing.T) {
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv(envAWSAccessKeyID, "AKIDENV")
+		t.Setenv(envAWSSecretAccessKey, "env-secret")
+		t.Setenv(envAWSSessionToken, "")
+
+		creds, err := defaultAWSCredentials(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, &AWSCredentials{
+			AccessKeyID:     "AKIDENV",
+			SecretAccessKey: "env-secret",
+		}, creds)
+	})
+
+	t.Run("shared credentials file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "credentials")
+		require.NoError(t, os.WriteFile(path, []byte(`# comment
+[default]
+aws_access_key_id = AKIDDEFAULT
+aws_secret_access_key = default-secret
+
+[ci]
+aws_access_key_id = AKIDCI
+aws_secret_access_key = ci-secret
+aws_session_token = ci-session
+`), 0o600))
+
+		t.Setenv(envAWSAccessKeyID, "")
+		t.Setenv(envAWSSecretAccessKey, "")
+		t.Setenv(envAWSSharedCredentialsFile, path)
+		t.Setenv(envAWSProfile, "ci")
+
+		creds, err := defaultAWSCredentials(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, &AWSCredentials{
+			AccessKeyID:     "AKIDCI",
+			SecretAccessKey: "ci-secret",
+			SessionToken:    "ci-session",
+		}, creds)
+
+		t.Setenv(envAWSProfile, "missing")
+		_, err = defaultAWSCredentials(t.Context())
+		require.ErrorContains(t, err, "profile missing not found")
+	})
+}
@@ -0,0 +1,177 @@
+package vaulty
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	hashiVault "github.com/hashicorp/vault/api"
+)
+
+// clientCertFunc returns the client certificate presented to Vault during the TLS handshake.
+type clientCertFunc = func(*tls.CertificateRequestInfo) (*tls.Certificate, error)
+
+// certFileLoader loads a client certificate from disk, reloading it when either file changes.
+type certFileLoader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	certMod time.Time
+	keyMod  time.Time
+}
+
+// newCertFileLoader creates a loader for the given files, checking that they hold a valid key pair.
+func newCertFileLoader(certFile, keyFile string) (*certFileLoader, error) {
+	l := &certFileLoader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+
+	if _, err := l.load(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// getClientCertificate implements tls.Config.GetClientCertificate.
+func (l *certFileLoader) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return l.load()
+}
+
+// load returns the key pair, reading it from disk again if either file has been modified.
+func (l *certFileLoader) load() (*tls.Certificate, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	certInfo, err := os.Stat(l.certFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to stat client certificate: %w", err)
+	}
+
+	keyInfo, err := os.Stat(l.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to stat client key: %w", err)
+	}
+
+	if l.cert != nil && certInfo.ModTime().Equal(l.certMod) && keyInfo.ModTime().Equal(l.keyMod) {
+		return l.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load client key pair: %w", err)
+	}
+
+	l.cert = &cert
+	l.certMod = certInfo.ModTime()
+	l.keyMod = keyInfo.ModTime()
+
+	return l.cert, nil
+}
+
+// configureClientCert makes the HTTP client of the config present the client certificate to Vault.
+func configureClientCert(config *hashiVault.Config, getCert clientCertFunc) error {
+	if config.HttpClient == nil {
+		config.HttpClient = hashiVault.DefaultConfig().HttpClient
+	}
+
+	transport, ok := config.HttpClient.Transport.(*http.Transport)
+	if !ok {
+		return errors.New("http client transport does not support TLS configuration")
+	}
+
+	// The HTTP client may have been passed in with WithConfig, so it is copied rather than changed in place.
+	transport = transport.Clone()
+	httpClient := *config.HttpClient
+	httpClient.Transport = transport
+	config.HttpClient = &httpClient
+
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	transport.TLSClientConfig.Certificates = nil
+	transport.TLSClientConfig.GetClientCertificate = getCert
+
+	return nil
+}
+
+// NewCertAuth creates an AuthMethod that logs in with the TLS certificate auth method.
+// The key pair is read from the given files and reloaded whenever either file changes.
+// The client presents the certificate on every request, not only on login.
+func NewCertAuth(certFile, keyFile string, opts ...AuthOption) (AuthMethod, error) {
+	if certFile == "" {
+		return nil, errors.New("certificate file is empty")
+	} else if keyFile == "" {
+		return nil, errors.New("key file is empty")
+	}
+
+	loader, err := newCertFileLoader(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCertAuth(loader.getClientCertificate, opts...), nil
+}
+
+// NewCertAuthFromCertificate creates an AuthMethod that logs in with the TLS certificate auth method using the given certificate.
+func NewCertAuthFromCertificate(cert *tls.Certificate, opts ...AuthOption) (AuthMethod, error) {
+	if cert == nil {
+		return nil, errors.New("certificate is nil")
+	} else if len(cert.Certificate) == 0 {
+		return nil, errors.New("certificate is empty")
+	}
+
+	return newCertAuth(func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return cert, nil
+	}, opts...), nil
+}
+
+// newCertAuth creates an AuthMethod that logs in with the TLS certificate auth method using the given certificate source.
+func newCertAuth(getCert clientCertFunc, opts ...AuthOption) AuthMethod {
+	o := newAuthOptions(defaultMountCert, opts...)
+	return &builtinAuth{
+		name:      authNameCert,
+		renewable: true,
+		login: func(ctx context.Context, v *hashiVault.Client) (*hashiVault.Secret, error) {
+			return certLogin(ctx, v, o)
+		},
+		clientCert: getCert,
+	}
+}
+
+// certLogin authenticates with Vault using the TLS certificate auth method.
+// The client certificate is presented by the HTTP transport of the client.
+func certLogin(ctx context.Context, client *hashiVault.Client, o *authOptions) (*hashiVault.Secret, error) {
+	ctx, cancel := o.loginContext(ctx)
+	defer cancel()
+
+	// The certificate is only presented during a TLS handshake, so the login must not reuse a connection
+	// opened before the key pair was rotated.
+	client.CloneConfig().HttpClient.CloseIdleConnections()
+
+	data := make(map[string]any)
+	if o.certRole != "" {
+		data["name"] = o.certRole
+	}
+
+	authInfo, err := client.Logical().WriteWithContext(ctx, o.loginPath(), data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to login to cert auth method: %w", err)
+	}
+	if authInfo == nil {
+		return nil, errors.New("no auth info was returned after login")
+	}
+
+	return authInfo, nil
+}
@@ -0,0 +1,182 @@
+package vaulty
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	hashiVault "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestKeyPair writes a self-signed client key pair with the given common name and returns the file paths.
+func writeTestKeyPair(t *testing.T, dir, commonName string, modTime time.Time) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "client.crt")
+	keyFile = filepath.Join(dir, "client.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+
+	return certFile, keyFile
+}
+
+// newTestTLSVaultServer starts a stand-in Vault server that requests client certificates.
+func newTestTLSVaultServer(t *testing.T, handler http.Handler) (*httptest.Server, *hashiVault.Config) {
+	t.Helper()
+
+	srv := httptest.NewUnstartedServer(handler)
+	srv.TLS = &tls.Config{
+		ClientAuth: tls.RequestClientCert,
+		MinVersion: tls.VersionTLS12,
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	config := hashiVault.DefaultConfig()
+	config.Address = srv.URL
+	transport, ok := config.HttpClient.Transport.(*http.Transport)
+	require.True(t, ok)
+	transport.TLSClientConfig.RootCAs = x509.NewCertPool()
+	transport.TLSClientConfig.RootCAs.AddCert(srv.Certificate())
+
+	return srv, config
+}
+
+func TestWithCertAuth(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu          sync.Mutex
+		commonNames []string
+		body        map[string]any
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/tls/login", func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		mu.Lock()
+		commonNames = append(commonNames, r.TLS.PeerCertificates[0].Subject.CommonName)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		mu.Unlock()
+
+		writeTestJSON(t, w, longLivedAuthResponse("cert-token"))
+	})
+	_, config := newTestTLSVaultServer(t, mux)
+
+	transport, ok := config.HttpClient.Transport.(*http.Transport)
+	require.True(t, ok)
+
+	dir := t.TempDir()
+	certFile, keyFile := writeTestKeyPair(t, dir, "first", time.Now().Add(-time.Minute))
+
+	vc, err := NewClient(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithCertAuth(certFile, keyFile, WithAuthMount("tls"), WithCertRole("web")),
+		WithConfig(config), // Applied after the cert auth to check the order does not matter
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	require.Equal(t, "cert-token", vc.Client().Token())
+
+	// The transport passed in with the config is left as it was.
+	require.Nil(t, transport.TLSClientConfig.GetClientCertificate)
+
+	// Rotate the key pair on disk and log in again. The connection of the first login is still open.
+	writeTestKeyPair(t, dir, "second", time.Now())
+
+	c, ok := vc.(*client)
+	require.True(t, ok)
+	_, err = c.reauthenticate()
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"first", "second"}, commonNames)
+	require.Equal(t, map[string]any{"name": "web"}, body)
+}
+
+func TestWithCertAuthFromCertificate(t *testing.T) {
+	t.Parallel()
+
+	var commonName string
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/cert/login", func(w http.ResponseWriter, r *http.Request) {
+		commonName = r.TLS.PeerCertificates[0].Subject.CommonName
+		writeTestJSON(t, w, longLivedAuthResponse("cert-token"))
+	})
+	_, config := newTestTLSVaultServer(t, mux)
+
+	certFile, keyFile := writeTestKeyPair(t, t.TempDir(), "in-memory", time.Now())
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	require.NoError(t, err)
+
+	vc, err := NewClient(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithConfig(config),
+		WithCertAuthFromCertificate(&cert),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	require.Equal(t, "in-memory", commonName)
+}
+
+func TestNewCertAuthFromCertificate_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCertAuthFromCertificate(nil)
+	require.EqualError(t, err, "certificate is nil")
+
+	_, err = NewCertAuthFromCertificate(&tls.Certificate{})
+	require.EqualError(t, err, "certificate is empty")
+}
+
+func TestWithCertAuth_InvalidFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, err := NewClient(WithCertAuth(filepath.Join(dir, "missing.crt"), filepath.Join(dir, "missing.key")))
+	require.ErrorContains(t, err, "unable to stat client certificate")
+}
@@ -0,0 +1,152 @@
+package vaulty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	hashiVault "github.com/hashicorp/vault/api"
+)
+
+// loggingMethod is implemented by auth methods that log through the client's logger.
+type loggingMethod interface {
+	setLogger(l *slog.Logger)
+}
+
+// authChain is an AuthMethod that tries a list of auth methods in order and uses the first that succeeds.
+type authChain struct {
+	methods []AuthMethod
+
+	mu     sync.Mutex
+	l      *slog.Logger
+	winner AuthMethod
+}
+
+// NewAuthChain creates an AuthMethod that tries the given auth methods in order on every login
+// and uses the first that succeeds. If they all fail, the error lists the failure of each method.
+func NewAuthChain(methods ...hashiVault.AuthMethod) (AuthMethod, error) {
+	if len(methods) == 0 {
+		return nil, errors.New("auth chain is empty")
+	}
+
+	chain := &authChain{
+		methods: make([]AuthMethod, 0, len(methods)),
+		l:       slog.Default(),
+	}
+	for i, method := range methods {
+		if method == nil {
+			return nil, fmt.Errorf("auth method %d in the chain: %w", i, ErrInvalidAuth)
+		}
+		chain.methods = append(chain.methods, asAuthMethod(method))
+	}
+
+	for _, method := range chain.methods {
+		if _, ok := method.(watchingMethod); ok {
+			return &watchingAuthChain{authChain: chain}, nil
+		}
+	}
+
+	return chain, nil
+}
+
+// Login logs in with the first auth method of the chain that succeeds.
+func (a *authChain) Login(ctx context.Context, client *hashiVault.Client) (*hashiVault.Secret, error) {
+	errs := make([]error, 0, len(a.methods))
+	for _, method := range a.methods {
+		// A failed method may leave its token on the client, which must not be sent by the next one.
+		client.ClearToken()
+
+		authInfo, err := method.Login(ctx, client)
+		if err == nil && authInfo != nil {
+			a.setWinner(method)
+			return authInfo, nil
+		} else if err == nil {
+			err = errors.New("no auth info was returned after login")
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", method.Name(), err))
+		a.logger().Debug("auth method in chain failed",
+			slog.String(loggingKeyAuthMethod, method.Name()),
+			slog.String(loggingKeyError, err.Error()),
+		)
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, fmt.Errorf("no auth method in the chain succeeded: %w", errors.Join(errs...))
+}
+
+// setWinner records the auth method that logged in, logging it when it changes.
+func (a *authChain) setWinner(method AuthMethod) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.winner != method {
+		a.l.Info("logged in with auth method from chain", slog.String(loggingKeyAuthMethod, method.Name()))
+	}
+	a.winner = method
+}
+
+// logger returns the logger of the chain.
+func (a *authChain) logger() *slog.Logger {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.l
+}
+
+// setLogger sets the logger used to report which auth method logged in.
+func (a *authChain) setLogger(l *slog.Logger) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.l = l
+}
+
+// Name returns the name of the auth method chain.
+func (*authChain) Name() string {
+	return authNameChain
+}
+
+// Renewable reports whether the token of the auth method that last logged in should be renewed.
+func (a *authChain) Renewable() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.winner != nil && a.winner.Renewable()
+}
+
+// clientCertificate returns the client certificate of the first auth method in the chain that presents one.
+// The certificate is configured on the transport, so it is presented whichever method logs in.
+func (a *authChain) clientCertificate() clientCertFunc {
+	for _, method := range a.methods {
+		if cm, ok := method.(clientCertMethod); ok {
+			if cert := cm.clientCertificate(); cert != nil {
+				return cert
+			}
+		}
+	}
+	return nil
+}
+
+// watchingAuthChain is an authChain holding at least one auth method whose credentials are watched.
+type watchingAuthChain struct {
+	*authChain
+}
+
+// watchCredentials runs the credential watchers of the auth methods in the chain until the context is cancelled.
+// A change to any of them logs in again through the whole chain.
+func (a *watchingAuthChain) watchCredentials(ctx context.Context, l *slog.Logger, relogin func()) {
+	var wg sync.WaitGroup
+	for _, method := range a.methods {
+		if wm, ok := method.(watchingMethod); ok {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				wm.watchCredentials(ctx, l, relogin)
+			}()
+		}
+	}
+	wg.Wait()
+}
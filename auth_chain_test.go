@@ -0,0 +1,197 @@
+package vaulty
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	hashiVault "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAuthChain(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/userpass/login/user", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		writeTestJSON(t, w, map[string]any{"errors": []string{"invalid username or password"}})
+	})
+	mux.HandleFunc("PUT /v1/auth/approle/login", func(w http.ResponseWriter, r *http.Request) {
+		require.Empty(t, r.Header.Get("X-Vault-Token"))
+		writeTestJSON(t, w, longLivedAuthResponse("approle-token"))
+	})
+	srv := newTestVaultServer(t, mux)
+
+	userPass, err := NewUserPassAuth("user", "pass")
+	require.NoError(t, err)
+	appRole, err := NewAppRoleAuth("role-id", "secret-id")
+	require.NoError(t, err)
+
+	logs := new(bytes.Buffer)
+	vc, err := NewClient(
+		WithLogger(slog.New(slog.NewTextHandler(logs, nil))),
+		WithAddr(srv.URL),
+		WithAuthChain(userPass, appRole),
+	)
+	require.NoError(t, err)
+	require.NoError(t, vc.Close(context.Background()))
+
+	require.Equal(t, "approle-token", vc.Client().Token())
+	require.Contains(t, logs.String(), "auth_method=approle")
+}
+
+func TestWithAuthChain_AllFail(t *testing.T) {
+	t.Parallel()
+
+	errFirst := errors.New("first failed")
+	errSecond := errors.New("second failed")
+
+	srv := newTestVaultServer(t, http.NewServeMux())
+
+	_, err := NewClient(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithAuthChain(
+			&builtinAuth{name: "first", login: func(context.Context, *hashiVault.Client) (*hashiVault.Secret, error) {
+				return nil, errFirst
+			}},
+			&builtinAuth{name: "second", login: func(context.Context, *hashiVault.Client) (*hashiVault.Secret, error) {
+				return nil, errSecond
+			}},
+		),
+	)
+	require.ErrorIs(t, err, errFirst)
+	require.ErrorIs(t, err, errSecond)
+	require.ErrorContains(t, err, "first: first failed")
+	require.ErrorContains(t, err, "second: second failed")
+}
+
+func TestWithAuthChain_Relogin(t *testing.T) {
+	t.Parallel()
+
+	// The preferred method is unavailable at startup, so the fallback logs in with a token that expires straight away.
+	// On the re-login the chain is tried from the start again and the preferred method wins.
+	var preferredLogins, fallbackLogins atomic.Int32
+	preferred := &builtinAuth{
+		name:      "preferred",
+		renewable: true,
+		login: func(context.Context, *hashiVault.Client) (*hashiVault.Secret, error) {
+			if preferredLogins.Add(1) == 1 {
+				return nil, errors.New("not available yet")
+			}
+			return longLivedAuthSecret("preferred-token"), nil
+		},
+	}
+	fallback := &builtinAuth{
+		name:      "fallback",
+		renewable: true,
+		login: func(context.Context, *hashiVault.Client) (*hashiVault.Secret, error) {
+			fallbackLogins.Add(1)
+			return expiredAuthSecret("fallback-token"), nil
+		},
+	}
+
+	srv := newTestVaultServer(t, http.NewServeMux())
+
+	vc, err := NewClient(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithAuthChain(preferred, fallback),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	require.Eventually(t, func() bool {
+		return vc.Client().Token() == "preferred-token"
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, int32(2), preferredLogins.Load())
+	require.Equal(t, int32(1), fallbackLogins.Load())
+}
+
+func TestWithAuthChain_FallbackToTokenFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "sink")
+	require.NoError(t, os.WriteFile(path, []byte("agent-token-1\n"), 0o600))
+
+	// AppRole logs in once with a token that expires straight away and is then unavailable,
+	// so the chain falls back to a token file whose token is not renewed by the client.
+	var (
+		approleLogins    atomic.Int64
+		approleAvailable atomic.Bool
+		approleFlaky     atomic.Bool
+	)
+	approleAvailable.Store(true)
+	approleFlaky.Store(true)
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/approle/login", func(w http.ResponseWriter, _ *http.Request) {
+		approleLogins.Add(1)
+		if !approleAvailable.Load() {
+			writeTestVaultError(t, w, http.StatusBadRequest, "invalid secret id")
+			return
+		}
+		if approleFlaky.Load() {
+			approleAvailable.Store(false)
+		}
+		writeTestJSON(t, w, expiredAuthResponse("approle-token"))
+	})
+	mux.HandleFunc("GET /v1/auth/token/lookup-self", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(t, w, tokenLookupResponse(r.Header.Get("X-Vault-Token"), false, 3600))
+	})
+	srv := newTestVaultServer(t, mux)
+
+	approleAuth, err := NewAppRoleAuth("role-id", "secret-id")
+	require.NoError(t, err)
+	tokenFileAuth, err := NewTokenFileAuth(path, WithPollInterval(time.Millisecond))
+	require.NoError(t, err)
+
+	vc, err := NewClient(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithAuthChain(approleAuth, tokenFileAuth),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	require.Eventually(t, func() bool {
+		return vc.Client().Token() == "agent-token-1"
+	}, 5*time.Second, time.Millisecond)
+
+	// The token file token has no lease to renew, so the client stops logging in again.
+	time.Sleep(50 * time.Millisecond)
+	stoppedAt := approleLogins.Load()
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, stoppedAt, approleLogins.Load())
+
+	// Once AppRole is available again, a new token in the file logs in through the whole chain,
+	// and the renewable AppRole token is renewed again.
+	approleFlaky.Store(false)
+	approleAvailable.Store(true)
+	require.NoError(t, os.WriteFile(path, []byte("agent-token-2\n"), 0o600))
+
+	require.Eventually(t, func() bool {
+		return approleLogins.Load() > stoppedAt+5
+	}, 5*time.Second, time.Millisecond)
+}
+
+func TestNewAuthChain_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewAuthChain()
+	require.EqualError(t, err, "auth chain is empty")
+
+	_, err = NewAuthChain(nil)
+	require.ErrorIs(t, err, ErrInvalidAuth)
+}
@@ -0,0 +1,85 @@
+package vaulty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	hashiVault "github.com/hashicorp/vault/api"
+)
+
+// JWTProvider is a function that returns the JWT to log in with.
+type JWTProvider func(ctx context.Context) (string, error)
+
+// JWTFromFile returns a JWTProvider that reads the JWT from the given file every time it is called.
+// This picks up tokens that are rotated on disk, such as workload identity tokens.
+func JWTFromFile(path string) JWTProvider {
+	return func(context.Context) (string, error) {
+		b, err := os.ReadFile(path) // nolint:gosec // The path is provided by the application
+		if err != nil {
+			return "", fmt.Errorf("unable to read jwt file: %w", err)
+		}
+
+		jwt := strings.TrimSpace(string(b))
+		if jwt == "" {
+			return "", fmt.Errorf("jwt file %s is empty", path)
+		}
+
+		return jwt, nil
+	}
+}
+
+// NewJWTAuth creates an AuthMethod that logs in with the JWT auth method.
+// The JWT provider is called on every login, so it can return a fresh token each time.
+func NewJWTAuth(role string, jwtProvider JWTProvider, opts ...AuthOption) (AuthMethod, error) {
+	if role == "" {
+		return nil, errors.New("role name is empty")
+	} else if jwtProvider == nil {
+		return nil, errors.New("jwt provider is nil")
+	}
+
+	o := newAuthOptions(defaultMountJWT, opts...)
+	return &builtinAuth{
+		name:      authNameJWT,
+		renewable: true,
+		login: func(ctx context.Context, v *hashiVault.Client) (*hashiVault.Secret, error) {
+			return jwtLogin(ctx, v, role, jwtProvider, o)
+		},
+	}, nil
+}
+
+// NewJWTAuthFromFile creates an AuthMethod that logs in with the JWT auth method using a JWT read from the given file.
+// The file is read again on every login, so rotated tokens are picked up.
+func NewJWTAuthFromFile(role, path string, opts ...AuthOption) (AuthMethod, error) {
+	if path == "" {
+		return nil, errors.New("jwt file path is empty")
+	}
+
+	return NewJWTAuth(role, JWTFromFile(path), opts...)
+}
+
+// jwtLogin authenticates with Vault using the JWT auth method.
+func jwtLogin(ctx context.Context, client *hashiVault.Client, role string, jwtProvider JWTProvider, o *authOptions) (*hashiVault.Secret, error) {
+	ctx, cancel := o.loginContext(ctx)
+	defer cancel()
+
+	jwt, err := jwtProvider(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get jwt: %w", err)
+	}
+
+	authInfo, err := client.Logical().WriteWithContext(ctx, o.loginPath(), map[string]any{
+		"role": role,
+		"jwt":  jwt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to login to jwt auth method: %w", err)
+	}
+	if authInfo == nil {
+		return nil, errors.New("no auth info was returned after login")
+	}
+
+	return authInfo, nil
+}
@@ -0,0 +1,137 @@
+package vaulty
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithJWTAuth(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		opts      []AuthOption
+		loginPath string
+	}{
+		{
+			name:      "default mount",
+			opts:      nil,
+			loginPath: "/v1/auth/jwt/login",
+		},
+		{
+			name:      "custom mount",
+			opts:      []AuthOption{WithAuthMount("jwt-ci")},
+			loginPath: "/v1/auth/jwt-ci/login",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var body map[string]any
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT "+tt.loginPath, func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				writeTestJSON(t, w, longLivedAuthResponse("jwt-token"))
+			})
+			srv := newTestVaultServer(t, mux)
+
+			vc, err := NewClient(
+				WithLogger(slog.New(slog.DiscardHandler)),
+				WithAddr(srv.URL),
+				WithJWTAuth("ci", func(context.Context) (string, error) {
+					return "header.payload.signature", nil
+				}, tt.opts...),
+			)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				require.NoError(t, vc.Close(context.Background()))
+			})
+
+			require.Equal(t, "jwt-token", vc.Client().Token())
+			require.Equal(t, map[string]any{
+				"role": "ci",
+				"jwt":  "header.payload.signature",
+			}, body)
+		})
+	}
+}
+
+func TestWithJWTAuth_ProviderError(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestVaultServer(t, http.NewServeMux())
+
+	_, err := NewClient(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithJWTAuth("ci", func(context.Context) (string, error) {
+			return "", errors.New("identity token unavailable")
+		}),
+	)
+	require.ErrorContains(t, err, "identity token unavailable")
+}
+
+func TestWithJWTAuthFromFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("first.jwt.token\n"), 0o600))
+
+	var (
+		mu   sync.Mutex
+		jwts []string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/jwt/login", func(w http.ResponseWriter, r *http.Request) {
+		body := make(map[string]string)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		mu.Lock()
+		jwts = append(jwts, body["jwt"])
+		mu.Unlock()
+		writeTestJSON(t, w, longLivedAuthResponse("jwt-token"))
+	})
+	srv := newTestVaultServer(t, mux)
+
+	vc, err := NewClient(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithJWTAuthFromFile("ci", path),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	// Rotate the token on disk and log in again.
+	require.NoError(t, os.WriteFile(path, []byte("second.jwt.token"), 0o600))
+
+	c, ok := vc.(*client)
+	require.True(t, ok)
+	_, err = c.reauthenticate()
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"first.jwt.token", "second.jwt.token"}, jwts)
+}
+
+func TestJWTFromFile_Empty(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("\n"), 0o600))
+
+	_, err := JWTFromFile(path)(t.Context())
+	require.ErrorContains(t, err, "is empty")
+}
@@ -4,23 +4,56 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"time"
 
 	hashiVault "github.com/hashicorp/vault/api"
 	auth "github.com/hashicorp/vault/api/auth/kubernetes"
 )
 
+// NewKubernetesServiceAccountAuth creates an AuthMethod that logs in with the Kubernetes auth method
+// using the service account token mounted into the pod.
+func NewKubernetesServiceAccountAuth(role string, opts ...AuthOption) (AuthMethod, error) {
+	if role == "" {
+		return nil, errors.New("role name is empty")
+	}
+
+	return newKubernetesAuth(role, auth.WithServiceAccountTokenPath(kubernetesServiceAccountTokenPath), opts...), nil
+}
+
+// NewKubernetesAuth creates an AuthMethod that logs in with the Kubernetes auth method using the given token.
+func NewKubernetesAuth(role, token string, opts ...AuthOption) (AuthMethod, error) {
+	if role == "" {
+		return nil, errors.New("role name is empty")
+	} else if token == "" {
+		return nil, errors.New("token is empty")
+	}
+
+	return newKubernetesAuth(role, auth.WithServiceAccountToken(token), opts...), nil
+}
+
+// newKubernetesAuth creates an AuthMethod that logs in with the Kubernetes auth method using the given token source.
+func newKubernetesAuth(role string, token auth.LoginOption, opts ...AuthOption) AuthMethod {
+	o := newAuthOptions(defaultMountKubernetes, opts...)
+	return &builtinAuth{
+		name:      authNameKubernetes,
+		renewable: true,
+		login: func(ctx context.Context, v *hashiVault.Client) (*hashiVault.Secret, error) {
+			return kubernetesLogin(ctx, v, role, token, o)
+		},
+	}
+}
+
 // kubernetesLogin authenticates with Vault using the Kubernetes auth method.
-func kubernetesLogin(client *hashiVault.Client, role string, token auth.LoginOption) (*hashiVault.Secret, error) {
+func kubernetesLogin(ctx context.Context, client *hashiVault.Client, role string, token auth.LoginOption, o *authOptions) (*hashiVault.Secret, error) {
 	k8sAuth, err := auth.NewKubernetesAuth(
 		role,
 		token,
+		auth.WithMountPath(o.mount),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to initialize kubernetes auth method: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := o.loginContext(ctx)
 	defer cancel()
 
 	authInfo, err := client.Auth().Login(ctx, k8sAuth)
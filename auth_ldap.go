@@ -0,0 +1,52 @@
+package vaulty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	hashiVault "github.com/hashicorp/vault/api"
+)
+
+// NewLDAPAuth creates an AuthMethod that logs in with the LDAP auth method.
+// The password is read from the source on every login.
+func NewLDAPAuth(username string, password CredentialSource, opts ...AuthOption) (AuthMethod, error) {
+	if username == "" {
+		return nil, errors.New("username is empty")
+	} else if password == nil {
+		return nil, errors.New("password source is nil")
+	}
+
+	o := newAuthOptions(defaultMountLDAP, opts...)
+	return &builtinAuth{
+		name:      authNameLDAP,
+		renewable: true,
+		login: func(ctx context.Context, v *hashiVault.Client) (*hashiVault.Secret, error) {
+			return ldapLogin(ctx, v, username, password, o)
+		},
+	}, nil
+}
+
+// ldapLogin authenticates with Vault using the LDAP auth method.
+func ldapLogin(ctx context.Context, client *hashiVault.Client, username string, password CredentialSource, o *authOptions) (*hashiVault.Secret, error) {
+	pw, err := password()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get ldap password: %w", err)
+	}
+
+	ctx, cancel := o.loginContext(ctx)
+	defer cancel()
+
+	authInfo, err := client.Logical().WriteWithContext(ctx, o.loginPath()+"/"+url.PathEscape(username), map[string]any{
+		"password": pw,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to login to ldap auth method: %w", err)
+	}
+	if authInfo == nil {
+		return nil, errors.New("no auth info was returned after login")
+	}
+
+	return authInfo, nil
+}
@@ -0,0 +1,85 @@
+package vaulty
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLDAPAuth(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("from-file\n"), 0o600))
+	t.Setenv("VAULTY_TEST_LDAP_PASSWORD", "from-env")
+
+	tests := []struct {
+		name      string
+		password  CredentialSource
+		opts      []AuthOption
+		loginPath string
+		want      string
+	}{
+		{
+			name:      "string",
+			password:  CredentialFromString("from-string"),
+			loginPath: "/v1/auth/ldap/login/jdoe",
+			want:      "from-string",
+		},
+		{
+			name:      "file",
+			password:  CredentialFromFile(passwordFile),
+			loginPath: "/v1/auth/ldap/login/jdoe",
+			want:      "from-file",
+		},
+		{
+			name:      "env with custom mount",
+			password:  CredentialFromEnv("VAULTY_TEST_LDAP_PASSWORD"),
+			opts:      []AuthOption{WithAuthMount("corp-ldap")},
+			loginPath: "/v1/auth/corp-ldap/login/jdoe",
+			want:      "from-env",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]any
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT "+tt.loginPath, func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				writeTestJSON(t, w, longLivedAuthResponse("ldap-token"))
+			})
+			srv := newTestVaultServer(t, mux)
+
+			vc, err := NewClient(
+				WithLogger(slog.New(slog.DiscardHandler)),
+				WithAddr(srv.URL),
+				WithLDAPAuth("jdoe", tt.password, tt.opts...),
+			)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				require.NoError(t, vc.Close(context.Background()))
+			})
+
+			require.Equal(t, "ldap-token", vc.Client().Token())
+			require.Equal(t, map[string]any{"password": tt.want}, body)
+		})
+	}
+}
+
+func TestWithLDAPAuth_MissingPassword(t *testing.T) {
+	t.Setenv("VAULTY_TEST_LDAP_PASSWORD", "")
+
+	srv := newTestVaultServer(t, http.NewServeMux())
+
+	_, err := NewClient(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithLDAPAuth("jdoe", CredentialFromEnv("VAULTY_TEST_LDAP_PASSWORD")),
+	)
+	require.ErrorContains(t, err, "environment variable VAULTY_TEST_LDAP_PASSWORD is not set")
+}
@@ -0,0 +1,105 @@
+package vaulty
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	hashiVault "github.com/hashicorp/vault/api"
+)
+
+// AuthMethod is a login flow that the client uses to authenticate with Vault.
+// Implement it to plug an auth method that vaulty does not ship into the client's renewal lifecycle.
+type AuthMethod interface {
+	// Login logs in to Vault and returns the secret holding the new token.
+	// The client swaps the token in itself, so Login must not start any background work.
+	Login(ctx context.Context, client *hashiVault.Client) (*hashiVault.Secret, error)
+
+	// Name returns a short name for the auth method, used in logs and errors.
+	Name() string
+
+	// Renewable reports whether the client should renew the token returned by Login,
+	// logging in again once it can no longer be renewed.
+	Renewable() bool
+}
+
+// clientCertMethod is implemented by auth methods that present a client certificate to Vault.
+type clientCertMethod interface {
+	clientCertificate() clientCertFunc
+}
+
+// watchingMethod is implemented by auth methods that detect new credentials themselves.
+// watchCredentials blocks until the context is cancelled, calling relogin whenever the credentials change.
+type watchingMethod interface {
+	watchCredentials(ctx context.Context, l *slog.Logger, relogin func())
+}
+
+// builtinAuth is an AuthMethod backed by one of vaulty's login functions.
+type builtinAuth struct {
+	name       string
+	renewable  bool
+	login      loginFunc
+	clientCert clientCertFunc
+}
+
+// Login logs in to Vault using the login function.
+func (b *builtinAuth) Login(ctx context.Context, client *hashiVault.Client) (*hashiVault.Secret, error) {
+	return b.login(ctx, client)
+}
+
+// Name returns the name of the auth method.
+func (b *builtinAuth) Name() string {
+	return b.name
+}
+
+// Renewable reports whether the token should be renewed.
+func (b *builtinAuth) Renewable() bool {
+	return b.renewable
+}
+
+// clientCertificate returns the client certificate presented to Vault, if any.
+func (b *builtinAuth) clientCertificate() clientCertFunc {
+	return b.clientCert
+}
+
+// watchingAuth is a builtinAuth whose credentials are watched for changes, such as a token file.
+type watchingAuth struct {
+	*builtinAuth
+	watch func(ctx context.Context, l *slog.Logger, relogin func())
+}
+
+// watchCredentials watches the credentials of the auth method for changes.
+func (w *watchingAuth) watchCredentials(ctx context.Context, l *slog.Logger, relogin func()) {
+	w.watch(ctx, l, relogin)
+}
+
+// hashiAuthMethod adapts a hashiVault.AuthMethod, such as those in the upstream api/auth packages, to AuthMethod.
+type hashiAuthMethod struct {
+	method hashiVault.AuthMethod
+}
+
+// Login logs in to Vault through the upstream auth helper, which rejects logins that require MFA.
+func (h *hashiAuthMethod) Login(ctx context.Context, client *hashiVault.Client) (*hashiVault.Secret, error) {
+	return client.Auth().Login(ctx, h.method)
+}
+
+// Name returns the type name of the wrapped auth method.
+func (h *hashiAuthMethod) Name() string {
+	return fmt.Sprintf("%T", h.method)
+}
+
+// Renewable reports that tokens from upstream auth methods are renewed.
+func (*hashiAuthMethod) Renewable() bool {
+	return true
+}
+
+// asAuthMethod returns the method as an AuthMethod, wrapping it if it only implements hashiVault.AuthMethod.
+func asAuthMethod(method hashiVault.AuthMethod) AuthMethod {
+	if m, ok := method.(AuthMethod); ok {
+		return m
+	}
+
+	return &hashiAuthMethod{
+		method: method,
+	}
+}
@@ -0,0 +1,154 @@
+package vaulty
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	hashiVault "github.com/hashicorp/vault/api"
+	"github.com/hashicorp/vault/api/auth/approle"
+	"github.com/stretchr/testify/require"
+)
+
+// countingAuth is a custom AuthMethod that counts its logins and returns already expired tokens.
+type countingAuth struct {
+	renewable bool
+	logins    atomic.Int64
+}
+
+func (a *countingAuth) Login(context.Context, *hashiVault.Client) (*hashiVault.Secret, error) {
+	a.logins.Add(1)
+	return expiredAuthSecret("custom-token"), nil
+}
+
+func (*countingAuth) Name() string {
+	return "custom"
+}
+
+func (a *countingAuth) Renewable() bool {
+	return a.renewable
+}
+
+func TestWithAuthMethod_Upstream(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/approle/login", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestJSON(t, w, longLivedAuthResponse("approle-token"))
+	})
+	srv := newTestVaultServer(t, mux)
+
+	upstream, err := approle.NewAppRoleAuth("role-id", &approle.SecretID{FromString: "secret-id"})
+	require.NoError(t, err)
+
+	vc, err := NewClient(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithAuthMethod(upstream),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	require.Equal(t, "approle-token", vc.Client().Token())
+
+	c, ok := vc.(*client)
+	require.True(t, ok)
+	require.Equal(t, "*approle.AppRoleAuth", c.auth.Name())
+	require.True(t, c.auth.Renewable())
+}
+
+func TestWithAuthMethod_Custom(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		renewable bool
+		relogin   bool
+	}{
+		{
+			name:      "renewable tokens are renewed",
+			renewable: true,
+			relogin:   true,
+		},
+		{
+			name:      "non-renewable tokens are left alone",
+			renewable: false,
+			relogin:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			method := &countingAuth{renewable: tt.renewable}
+			srv := newTestVaultServer(t, http.NewServeMux())
+			vc, err := NewClient(
+				WithLogger(slog.New(slog.DiscardHandler)),
+				WithAddr(srv.URL),
+				WithAuthMethod(method),
+			)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				require.NoError(t, vc.Close(context.Background()))
+			})
+
+			require.Equal(t, "custom-token", vc.Client().Token())
+
+			if tt.relogin {
+				require.Eventually(t, func() bool {
+					return method.logins.Load() > 1
+				}, 5*time.Second, time.Millisecond)
+				return
+			}
+
+			time.Sleep(20 * time.Millisecond)
+			require.Equal(t, int64(1), method.logins.Load())
+		})
+	}
+}
+
+func TestWithAuthMethod_Nil(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewClient(WithAuthMethod(nil))
+	require.ErrorIs(t, err, ErrInvalidAuth)
+}
+
+func TestAuthMethod_WatchesCredentials(t *testing.T) {
+	t.Parallel()
+
+	approleAuth, err := NewAppRoleAuth("role-id", "secret-id")
+	require.NoError(t, err)
+	tokenFileAuth, err := NewTokenFileAuth("/run/vault/token")
+	require.NoError(t, err)
+	plainChain, err := NewAuthChain(approleAuth)
+	require.NoError(t, err)
+	watchingChain, err := NewAuthChain(approleAuth, tokenFileAuth)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method AuthMethod
+		want   bool
+	}{
+		{name: "approle", method: approleAuth, want: false},
+		{name: "token file", method: tokenFileAuth, want: true},
+		{name: "chain without a token file", method: plainChain, want: false},
+		{name: "chain with a token file", method: watchingChain, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, ok := tt.method.(watchingMethod)
+			require.Equal(t, tt.want, ok)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package vaulty
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AuthOption is a function that configures an auth method.
+type AuthOption func(o *authOptions)
+
+// authOptions holds the settings shared by the auth methods.
+type authOptions struct {
+	mount   string
+	timeout time.Duration
+
+	// pollInterval is how often credential files are checked for changes.
+	pollInterval time.Duration
+
+	// wrappedSecretID marks the AppRole secret ID source as returning a response-wrapping token.
+	wrappedSecretID bool
+
+	// certRole is the name of the certificate role to log in against.
+	certRole string
+
+	// AWS IAM settings
+	awsRegion      string
+	awsServerID    string
+	awsCredentials AWSCredentialsProvider
+}
+
+// newAuthOptions returns the auth options with the given default mount and the options applied.
+func newAuthOptions(defaultMount string, opts ...AuthOption) *authOptions {
+	o := &authOptions{
+		mount:        defaultMount,
+		timeout:      defaultLoginTimeout,
+		pollInterval: defaultPollInterval,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// loginPath returns the login path of the auth method.
+func (o *authOptions) loginPath() string {
+	return fmt.Sprintf("auth/%s/login", o.mount)
+}
+
+// loginContext returns the context for a login request, bounded by the login timeout.
+func (o *authOptions) loginContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, o.timeout)
+}
+
+// WithAuthMount sets the path the auth method is mounted at, e.g. "jwt-ci" for a method enabled at auth/jwt-ci.
+func WithAuthMount(mount string) AuthOption {
+	return func(o *authOptions) {
+		o.mount = mount
+	}
+}
+
+// WithLoginTimeout sets how long a single login request may take. Defaults to 5 seconds.
+func WithLoginTimeout(timeout time.Duration) AuthOption {
+	return func(o *authOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithPollInterval sets how often credential files, such as a Vault Agent token sink, are checked for changes.
+// Defaults to 5 seconds.
+func WithPollInterval(interval time.Duration) AuthOption {
+	return func(o *authOptions) {
+		o.pollInterval = interval
+	}
+}
+
+// WithWrappedSecretID marks the AppRole secret ID as response-wrapped. The secret ID source then returns
+// a wrapping token, which is unwrapped once on the first login; the unwrapped secret ID is kept for later logins.
+func WithWrappedSecretID() AuthOption {
+	return func(o *authOptions) {
+		o.wrappedSecretID = true
+	}
+}
+
+// WithCertRole sets the name of the certificate role used by the cert auth method.
+// Without it, Vault tries every role that matches the client certificate.
+func WithCertRole(name string) AuthOption {
+	return func(o *authOptions) {
+		o.certRole = name
+	}
+}
+
+// WithAWSRegion sets the region of the STS endpoint used by the AWS IAM auth method. Defaults to us-east-1.
+// The Vault AWS auth method must be configured with the matching STS endpoint.
+func WithAWSRegion(region string) AuthOption {
+	return func(o *authOptions) {
+		o.awsRegion = region
+	}
+}
+
+// WithAWSServerID sets the X-Vault-AWS-IAM-Server-ID header that is signed into the AWS IAM login request.
+func WithAWSServerID(serverID string) AuthOption {
+	return func(o *authOptions) {
+		o.awsServerID = serverID
+	}
+}
+
+// WithAWSCredentialsProvider sets the source of the AWS credentials used by the AWS IAM auth method.
+// By default, the credentials are read from the standard environment variables or the shared credentials file.
+func WithAWSCredentialsProvider(provider AWSCredentialsProvider) AuthOption {
+	return func(o *authOptions) {
+		o.awsCredentials = provider
+	}
+}
@@ -0,0 +1,99 @@
+package vaulty
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthOptions_Mount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		option    ClientOption
+		loginPath string
+	}{
+		{
+			name:      "approle",
+			option:    WithAppRoleAuth("role-id", "secret-id", WithAuthMount("approle-ci")),
+			loginPath: "/v1/auth/approle-ci/login",
+		},
+		{
+			name:      "userpass",
+			option:    WithUserPassAuth("user", "pass", WithAuthMount("people")),
+			loginPath: "/v1/auth/people/login/user",
+		},
+		{
+			name:      "kubernetes",
+			option:    WithKubernetesAuth("app", "sa-token", WithAuthMount("k8s-east")),
+			loginPath: "/v1/auth/k8s-east/login",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT "+tt.loginPath, func(w http.ResponseWriter, _ *http.Request) {
+				writeTestJSON(t, w, longLivedAuthResponse("mounted-token"))
+			})
+			srv := newTestVaultServer(t, mux)
+
+			vc, err := NewClient(
+				WithLogger(slog.New(slog.DiscardHandler)),
+				WithAddr(srv.URL),
+				tt.option,
+			)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				require.NoError(t, vc.Close(context.Background()))
+			})
+
+			require.Equal(t, "mounted-token", vc.Client().Token())
+		})
+	}
+}
+
+func TestAuthOptions_LoginTimeout(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/approle/login", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		writeTestJSON(t, w, longLivedAuthResponse("slow-token"))
+	})
+	srv := newTestVaultServer(t, mux)
+
+	_, err := NewClient(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithAppRoleAuth("role-id", "secret-id", WithLoginTimeout(10*time.Millisecond)),
+	)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestAuthOptions_ClientContext(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestVaultServer(t, http.NewServeMux())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := NewClient(
+		WithContext(ctx),
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithUserPassAuth("user", "pass"),
+	)
+	require.ErrorIs(t, err, context.Canceled)
+}
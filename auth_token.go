@@ -1,9 +1,194 @@
 package vaulty
 
-import hashiVault "github.com/hashicorp/vault/api"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	hashiVault "github.com/hashicorp/vault/api"
+)
+
+// NewTokenAuth creates an AuthMethod that uses the given token as is.
+// Renewable tokens, such as periodic tokens, are renewed by the client.
+// Only the WithLoginTimeout option applies, bounding the token lookup.
+func NewTokenAuth(token string, opts ...AuthOption) (AuthMethod, error) {
+	if token == "" {
+		return nil, errors.New("token is empty")
+	}
+
+	o := newAuthOptions("", opts...)
+	return &builtinAuth{
+		name:      authNameToken,
+		renewable: true,
+		login: func(ctx context.Context, v *hashiVault.Client) (*hashiVault.Secret, error) {
+			return tokenLogin(ctx, v, token, o)
+		},
+	}, nil
+}
+
+// NewTokenFileAuth creates an AuthMethod that uses the token in the given file, such as a Vault Agent sink.
+// The file is polled for changes and a new token is swapped in as soon as it is written.
+// The token is not renewed by the client, as that is the job of whatever writes the file.
+func NewTokenFileAuth(path string, opts ...AuthOption) (AuthMethod, error) {
+	if path == "" {
+		return nil, errors.New("token file path is empty")
+	}
+
+	o := newAuthOptions("", opts...)
+	tf := &tokenFile{
+		path: path,
+	}
+
+	return &watchingAuth{
+		builtinAuth: &builtinAuth{
+			name:      authNameTokenFile,
+			renewable: false,
+			login: func(ctx context.Context, v *hashiVault.Client) (*hashiVault.Secret, error) {
+				token, err := readTokenFile(path)
+				if err != nil {
+					return nil, err
+				}
+
+				authInfo, err := tokenLogin(ctx, v, token, o)
+				if err != nil {
+					return nil, err
+				}
+
+				tf.setCurrent(token)
+				return authInfo, nil
+			},
+		},
+		watch: func(ctx context.Context, l *slog.Logger, relogin func()) {
+			tf.watch(ctx, l, o.pollInterval, relogin)
+		},
+	}, nil
+}
 
 // tokenLogin authenticates with Vault using a token.
-func tokenLogin(v *hashiVault.Client, token string) (*hashiVault.Secret, error) {
+// Renewable tokens are returned as auth info so that the client renews them.
+func tokenLogin(ctx context.Context, v *hashiVault.Client, token string, o *authOptions) (*hashiVault.Secret, error) {
+	ctx, cancel := o.loginContext(ctx)
+	defer cancel()
+
 	v.SetToken(token)
-	return v.Auth().Token().LookupSelf()
+	lookup, err := v.Auth().Token().LookupSelfWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to look up token: %w", err)
+	} else if lookup == nil {
+		return nil, errors.New("no token info was returned after lookup")
+	}
+
+	return tokenAuthSecret(lookup, token)
+}
+
+// tokenAuthSecret converts a token lookup into auth info the lifetime watcher can renew.
+// Lookups of non-renewable tokens are returned unchanged, as there is nothing to renew.
+func tokenAuthSecret(lookup *hashiVault.Secret, token string) (*hashiVault.Secret, error) {
+	renewable, err := lookup.TokenIsRenewable()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read token renewability: %w", err)
+	} else if !renewable {
+		return lookup, nil
+	}
+
+	ttl, err := lookup.TokenTTL()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read token ttl: %w", err)
+	}
+
+	accessor, err := lookup.TokenAccessor()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read token accessor: %w", err)
+	}
+
+	policies, err := lookup.TokenPolicies()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read token policies: %w", err)
+	}
+
+	return &hashiVault.Secret{
+		Data: lookup.Data,
+		Auth: &hashiVault.SecretAuth{
+			ClientToken:   token,
+			Accessor:      accessor,
+			Policies:      policies,
+			Renewable:     true,
+			LeaseDuration: int(ttl.Seconds()),
+		},
+	}, nil
+}
+
+// tokenFile is a token file, such as a Vault Agent sink, and the token the client last logged in with.
+type tokenFile struct {
+	path string
+
+	mu      sync.Mutex
+	current string
+}
+
+// setCurrent records the token the client is logged in with.
+func (f *tokenFile) setCurrent(token string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.current = token
+}
+
+// changed reports whether the file holds a different token than the one the client is logged in with.
+// A token that failed to log in is retried on every call until the file changes again or it succeeds.
+func (f *tokenFile) changed() (bool, error) {
+	token, err := readTokenFile(f.path)
+	if err != nil {
+		return false, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return token != f.current, nil
+}
+
+// watch polls the file until the context is cancelled, calling relogin whenever the token changes.
+func (f *tokenFile) watch(ctx context.Context, l *slog.Logger, interval time.Duration, relogin func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed, err := f.changed()
+		if err != nil {
+			// The file may be mid-rewrite, so keep the current token and try again on the next tick.
+			l.Debug("unable to read token file", slog.String(loggingKeyError, err.Error()))
+			continue
+		} else if !changed {
+			continue
+		}
+
+		l.Info("token file changed, swapping token")
+		relogin()
+	}
+}
+
+// readTokenFile reads the token from the given file.
+func readTokenFile(path string) (string, error) {
+	b, err := os.ReadFile(path) // nolint:gosec // The path is provided by the application
+	if err != nil {
+		return "", fmt.Errorf("unable to read token file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", path)
+	}
+
+	return token, nil
 }
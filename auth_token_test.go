@@ -0,0 +1,138 @@
+package vaulty
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// tokenLookupResponse is a lookup-self response for the given token.
+func tokenLookupResponse(token string, renewable bool, ttl int) map[string]any {
+	return map[string]any{
+		"data": map[string]any{
+			"id":        token,
+			"accessor":  "accessor-" + token,
+			"policies":  []string{"default"},
+			"renewable": renewable,
+			"ttl":       ttl,
+		},
+	}
+}
+
+func TestWithTokenAuth_Renewal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		renewable bool
+		ttl       int
+		renews    bool
+	}{
+		{
+			name:      "renewable token is renewed",
+			renewable: true,
+			ttl:       3600,
+			renews:    true,
+		},
+		{
+			name:      "non-renewable token is not renewed",
+			renewable: false,
+			ttl:       0,
+			renews:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var lookups, renewals atomic.Int64
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /v1/auth/token/lookup-self", func(w http.ResponseWriter, r *http.Request) {
+				lookups.Add(1)
+				writeTestJSON(t, w, tokenLookupResponse(r.Header.Get("X-Vault-Token"), tt.renewable, tt.ttl))
+			})
+			mux.HandleFunc("PUT /v1/auth/token/renew-self", func(w http.ResponseWriter, _ *http.Request) {
+				renewals.Add(1)
+				writeTestJSON(t, w, map[string]any{
+					"auth": map[string]any{
+						"client_token":   "periodic-token",
+						"renewable":      true,
+						"lease_duration": 3600,
+					},
+				})
+			})
+			srv := newTestVaultServer(t, mux)
+
+			vc, err := NewClient(
+				WithLogger(slog.New(slog.DiscardHandler)),
+				WithAddr(srv.URL),
+				WithTokenAuth("periodic-token"),
+			)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				require.NoError(t, vc.Close(context.Background()))
+			})
+
+			if tt.renews {
+				require.Eventually(t, func() bool {
+					return renewals.Load() > 0
+				}, 5*time.Second, time.Millisecond)
+				return
+			}
+
+			time.Sleep(20 * time.Millisecond)
+			require.Zero(t, renewals.Load())
+			require.Equal(t, int64(1), lookups.Load())
+		})
+	}
+}
+
+func TestWithTokenFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "sink")
+	require.NoError(t, os.WriteFile(path, []byte("agent-token-1\n"), 0o600))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/auth/token/lookup-self", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(t, w, tokenLookupResponse(r.Header.Get("X-Vault-Token"), true, 3600))
+	})
+	srv := newTestVaultServer(t, mux)
+
+	vc, err := NewClient(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithTokenFile(path, WithPollInterval(time.Millisecond)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	require.Equal(t, "agent-token-1", vc.Client().Token())
+
+	// Vault Agent writes a new token to the sink.
+	require.NoError(t, os.WriteFile(path, []byte("agent-token-2\n"), 0o600))
+
+	require.Eventually(t, func() bool {
+		return vc.Client().Token() == "agent-token-2"
+	}, 5*time.Second, time.Millisecond)
+}
+
+func TestWithTokenFile_Missing(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewClient(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithTokenFile(filepath.Join(t.TempDir(), "missing")),
+	)
+	require.ErrorContains(t, err, "unable to read token file")
+}
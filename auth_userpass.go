@@ -4,14 +4,31 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"time"
 
 	hashiVault "github.com/hashicorp/vault/api"
 	auth "github.com/hashicorp/vault/api/auth/userpass"
 )
 
+// NewUserPassAuth creates an AuthMethod that logs in with the Userpass auth method.
+func NewUserPassAuth(username, password string, opts ...AuthOption) (AuthMethod, error) {
+	if username == "" {
+		return nil, errors.New("username is empty")
+	} else if password == "" {
+		return nil, errors.New("password is empty")
+	}
+
+	o := newAuthOptions(defaultMountUserPass, opts...)
+	return &builtinAuth{
+		name:      authNameUserPass,
+		renewable: true,
+		login: func(ctx context.Context, v *hashiVault.Client) (*hashiVault.Secret, error) {
+			return userPassLogin(ctx, v, username, password, o)
+		},
+	}, nil
+}
+
 // userPassLogin authenticates with Vault using the Userpass auth method.
-func userPassLogin(client *hashiVault.Client, username, password string) (*hashiVault.Secret, error) {
+func userPassLogin(ctx context.Context, client *hashiVault.Client, username, password string, o *authOptions) (*hashiVault.Secret, error) {
 	// WARNING: A plaintext password like this is obviously insecure.
 	// See the hashicorp/vault-examples repo for full examples of how to securely
 	// log in to Vault using various auth methods. This function is just
@@ -22,12 +39,13 @@ func userPassLogin(client *hashiVault.Client, username, password string) (*hashi
 		&auth.Password{
 			FromString: password,
 		},
+		auth.WithMountPath(o.mount),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to initialize userpass auth method: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := o.loginContext(ctx)
 	defer cancel()
 
 	authInfo, err := client.Auth().Login(ctx, userPassAuth)
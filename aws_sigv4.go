@@ -0,0 +1,109 @@
+package vaulty
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	awsSigningAlgorithm = "AWS4-HMAC-SHA256"
+	awsTimeFormat       = "20060102T150405Z"
+	awsDateFormat       = "20060102"
+)
+
+// signAWSRequest signs the request with AWS Signature Version 4, setting the
+// X-Amz-Date, X-Amz-Security-Token and Authorization headers.
+//
+// ref: https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_sigv-create-signed-request.html
+func signAWSRequest(req *http.Request, body []byte, creds *AWSCredentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format(awsTimeFormat)
+	date := now.UTC().Format(awsDateFormat)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	canonicalHeaders, signedHeaders := awsCanonicalHeaders(req)
+	payloadHash := sha256.Sum256(body)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsCanonicalPath(req.URL.EscapedPath()),
+		req.URL.Query().Encode(),
+		canonicalHeaders,
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", date, region, service)
+	stringToSign := strings.Join([]string{
+		awsSigningAlgorithm,
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalHash[:]),
+	}, "\n")
+
+	signingKey := awsHMAC([]byte("AWS4"+creds.SecretAccessKey), date)
+	signingKey = awsHMAC(signingKey, region)
+	signingKey = awsHMAC(signingKey, service)
+	signingKey = awsHMAC(signingKey, "aws4_request")
+	signature := hex.EncodeToString(awsHMAC(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsSigningAlgorithm, creds.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// awsCanonicalHeaders returns the canonical headers and the signed header list of the request.
+// The host header is always signed.
+func awsCanonicalHeaders(req *http.Request) (canonical, signed string) {
+	headers := map[string]string{
+		"host": req.URL.Host,
+	}
+	for k, v := range req.Header {
+		values := make([]string, 0, len(v))
+		for _, s := range v {
+			values = append(values, strings.Join(strings.Fields(s), " "))
+		}
+		headers[strings.ToLower(k)] = strings.Join(values, ",")
+	}
+
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, k := range names {
+		b.WriteString(k)
+		b.WriteString(":")
+		b.WriteString(headers[k])
+		b.WriteString("\n")
+	}
+
+	return b.String(), strings.Join(names, ";")
+}
+
+// awsCanonicalPath returns the canonical URI path of a request.
+func awsCanonicalPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// awsHMAC returns the HMAC-SHA256 of the data with the given key.
+func awsHMAC(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
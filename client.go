@@ -5,7 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"os"
+	"sync"
+	"time"
 
 	hashiVault "github.com/hashicorp/vault/api"
 )
@@ -19,6 +20,12 @@ var (
 
 	// ErrInvalidAuth is returned when the auth method is nil.
 	ErrInvalidAuth = errors.New("auth method is nil")
+
+	// ErrAuthRetriesExhausted is returned when the client gives up logging in to Vault again.
+	ErrAuthRetriesExhausted = errors.New("auth retries exhausted")
+
+	// errAuthNotRenewable stops the auth renewal loop when a re-login returns a token without a lease to renew.
+	errAuthNotRenewable = errors.New("auth info is not renewable")
 )
 
 // ClientHandler is an interface that provides access to the Vault client.
@@ -32,6 +39,9 @@ type Client interface {
 
 	// Path returns the secret path for the given name.
 	Path(name string, opts ...PathOption) Repository
+
+	// Close stops the background renewals started by the client and waits for them to finish.
+	Close(ctx context.Context) error
 }
 
 type (
@@ -39,7 +49,8 @@ type (
 	RenewalFunc = func() (*hashiVault.Secret, error)
 
 	// loginFunc is a function that logs in to Vault and returns the secret.
-	loginFunc = func(v *hashiVault.Client) (*hashiVault.Secret, error)
+	// It must not start any background work; renewal is owned by the client.
+	loginFunc = func(ctx context.Context, v *hashiVault.Client) (*hashiVault.Secret, error)
 )
 
 // client is a struct that implements the Client interface.
@@ -47,12 +58,52 @@ type client struct {
 	ctx       context.Context
 	l         *slog.Logger
 	kvv2Mount string
-	auth      loginFunc
-	config    *hashiVault.Config
+	auth      AuthMethod
+
+	// namespace is the namespace the client sends its requests to, when it is set. An empty namespace is the root namespace.
+	namespace *string
+
+	// loginNamespace is the namespace logins are sent to, when it differs from the namespace of the client.
+	loginNamespace *string
+
+	config *hashiVault.Config
+
+	// clientCert is presented to Vault during the TLS handshake when set.
+	clientCert clientCertFunc
+
+	// Auth lifecycle configuration
+	retryPolicy *RetryPolicy
+	onAuthError func(err error)
+	authErrCh   chan<- error
+
+	// Lease renewal configuration
+	onLeaseError func(name string, err error)
+
+	// Shutdown configuration
+	revokeOnClose bool
 
 	// Below are set on initialization
-	v         *hashiVault.Client
+	v      *hashiVault.Client
+	cancel context.CancelFunc
+
+	// Auth state, swapped on every login
+	authMu    sync.RWMutex
 	authCreds *hashiVault.Secret
+
+	// Whether the auth renewal loop is running
+	renewMu  sync.Mutex
+	renewing bool
+
+	// KV version of each mount, keyed by namespace and read from sys/mounts on first use
+	kvMountsMu sync.Mutex
+	kvMounts   map[string]map[string]int
+
+	// Background work, stopped by Close
+	lifecycleMu sync.Mutex
+	closed      bool
+	shutDown    bool
+	leases      map[*trackedLease]struct{}
+	wg          sync.WaitGroup
 }
 
 // NewClient creates a new Vault client with the given options.
@@ -61,10 +112,22 @@ func NewClient(opts ...ClientOption) (Client, error) {
 		ctx:       context.Background(),
 		l:         slog.Default(),
 		kvv2Mount: "",
+		namespace: nil,
 		auth:      nil,
 		config:    hashiVault.DefaultConfig(),
+
+		retryPolicy: DefaultRetryPolicy(),
+		onAuthError: nil,
+		authErrCh:   nil,
+
+		onLeaseError: nil,
+
+		revokeOnClose: false,
+
 		v:         nil,
 		authCreds: nil,
+		leases:    make(map[*trackedLease]struct{}),
+		kvMounts:  make(map[string]map[string]int),
 	}
 
 	for _, opt := range opts {
@@ -77,45 +140,286 @@ func NewClient(opts ...ClientOption) (Client, error) {
 		c.ctx = context.Background()
 	}
 
+	c.ctx, c.cancel = context.WithCancel(c.ctx)
+
+	if err := c.init(); err != nil {
+		c.cancel()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// init creates the Vault client, logs in and starts the auth renewal loop.
+func (c *client) init() error {
+	if c.clientCert != nil {
+		if err := configureClientCert(c.config, c.clientCert); err != nil {
+			return fmt.Errorf("unable to configure client certificate: %w", err)
+		}
+	}
+
 	vc, err := hashiVault.NewClient(c.config)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create vault client: %w", err)
+		return fmt.Errorf("unable to create vault client: %w", err)
 	} else if vc == nil {
-		return nil, ErrInvalidClient
+		return ErrInvalidClient
+	}
+
+	if c.namespace != nil {
+		setNamespace(vc, *c.namespace)
 	}
 
 	c.v = vc
 
 	if c.auth == nil {
-		return nil, ErrInvalidAuth
+		return ErrInvalidAuth
+	}
+
+	if lm, ok := c.auth.(loggingMethod); ok {
+		lm.setLogger(c.l)
 	}
 
-	authCreds, err := c.auth(c.v)
+	authCreds, err := c.login()
 	if err != nil {
-		return nil, fmt.Errorf("unable to authenticate with Vault: %w", err)
+		return fmt.Errorf("unable to authenticate with Vault: %w", err)
 	}
 
-	c.authCreds = authCreds
+	if err := c.startAuthRenewal(authCreds); err != nil {
+		return fmt.Errorf("unable to start auth renewal: %w", err)
+	}
 
-	return c, nil
+	if wm, ok := c.auth.(watchingMethod); ok {
+		if err := c.goBackground(func() {
+			wm.watchCredentials(c.ctx, c.l, c.relogin)
+		}); err != nil {
+			return fmt.Errorf("unable to start credential watcher: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// startAuthRenewal starts the auth renewal loop for the auth info, unless the loop is already running.
+// Re-logins happen inside this one loop, so it is the only renewal goroutine.
+func (c *client) startAuthRenewal(authInfo *hashiVault.Secret) error {
+	c.renewMu.Lock()
+	defer c.renewMu.Unlock()
+
+	if c.renewing || !c.renewsAuth(authInfo) {
+		return nil
+	}
+
+	if err := c.goBackground(c.renewAuthInfo); err != nil {
+		return err
+	}
+	c.renewing = true
+
+	return nil
+}
+
+// renewsAuth reports whether the auth info has a token lease for the renewal loop to renew.
+// Logins that return no auth info, or a non-renewable token without a TTL, have nothing to renew or wait
+// out, so the loop would log in again straight away.
+func (c *client) renewsAuth(authInfo *hashiVault.Secret) bool {
+	return c.auth.Renewable() && authInfo != nil && authInfo.Auth != nil &&
+		(authInfo.Auth.Renewable || authInfo.Auth.LeaseDuration > 0)
 }
 
 // renewAuthInfo renews the authentication information for the client.
+// When the token can no longer be renewed, the client logs in again according to
+// its retry policy. If it gives up, the error is reported and renewal stops.
+// Renewal also stops when the new login has no token lease to renew.
 func (c *client) renewAuthInfo() {
-	err := RenewLease(c.ctx, c.l, c, "auth", c.authCreds, func() (*hashiVault.Secret, error) {
-		authInfo, err := c.auth(c.v)
-		if err != nil {
-			return nil, fmt.Errorf("unable to renew auth info: %w", err)
+	err := renewLease(c.ctx, c.l, c, "auth", c.authInfo(), c.reauthenticateRenewable, nil)
+	switch {
+	case errors.Is(err, errAuthNotRenewable):
+		c.l.Debug("auth renewal stopped", slog.String(loggingKeyError, err.Error()))
+		return
+	case err == nil:
+	case c.ctx.Err() != nil:
+		c.l.Debug("auth renewal stopped", slog.String(loggingKeyError, err.Error()))
+	default:
+		c.reportAuthError(err)
+	}
+
+	c.renewMu.Lock()
+	c.renewing = false
+	c.renewMu.Unlock()
+}
+
+// reauthenticateRenewable logs in again for the renewal loop, returning errAuthNotRenewable
+// when the current token has no lease to renew, so that the loop stops.
+func (c *client) reauthenticateRenewable() (*hashiVault.Secret, error) {
+	if _, err := c.reauthenticate(); err != nil {
+		return nil, err
+	}
+
+	c.renewMu.Lock()
+	defer c.renewMu.Unlock()
+
+	// A re-login after a credential change may have swapped in a newer token, so the current one decides.
+	authInfo := c.authInfo()
+	if !c.renewsAuth(authInfo) {
+		c.renewing = false
+		return nil, errAuthNotRenewable
+	}
+
+	return authInfo, nil
+}
+
+// reportAuthError reports the error that stopped the auth renewal loop.
+func (c *client) reportAuthError(err error) {
+	c.l.Error("unable to renew auth info", slog.String(loggingKeyError, err.Error()))
+
+	if c.authErrCh != nil {
+		select {
+		case c.authErrCh <- err:
+		default:
+			c.l.Warn("auth error channel is full, dropping error")
+		}
+	}
+}
+
+// renewalRetryPolicy returns the policy the renewal functions of leases renewed with RenewLease are retried with.
+func (c *client) renewalRetryPolicy() *RetryPolicy {
+	return c.retryPolicy
+}
+
+// reportLeaseError reports the error that stopped the renewal of a lease renewed with RenewLease.
+func (c *client) reportLeaseError(name string, err error) {
+	c.l.Error("unable to renew lease",
+		slog.String(loggingKeySecretName, name),
+		slog.String(loggingKeyError, err.Error()),
+	)
+
+	if c.onLeaseError != nil {
+		c.onLeaseError(name, err)
+	}
+}
+
+// reauthenticate logs in to Vault again, backing off between failed attempts
+// until it succeeds or the retry policy is exhausted.
+func (c *client) reauthenticate() (*hashiVault.Secret, error) {
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		authInfo, err := c.login()
+		if err == nil {
+			return authInfo, nil
 		}
 
-		c.authCreds = authInfo
+		err = fmt.Errorf("unable to renew auth info: %w", err)
+		c.l.Warn("login attempt failed",
+			slog.String(loggingKeyAuthMethod, c.auth.Name()),
+			slog.Int(loggingKeyAttempt, attempt),
+			slog.String(loggingKeyError, err.Error()),
+		)
+
+		if c.onAuthError != nil {
+			c.onAuthError(err)
+		}
+
+		if c.retryPolicy.exhausted(attempt, time.Since(start)) {
+			return nil, fmt.Errorf("%w after %d attempts: %w", ErrAuthRetriesExhausted, attempt, err)
+		}
+
+		timer := time.NewTimer(c.retryPolicy.backoff(attempt))
+		select {
+		case <-c.ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("login retry cancelled: %w", c.ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// relogin logs in to Vault again after the auth method saw its credentials change.
+// On failure the current token is kept and the error is reported to the auth error hook.
+func (c *client) relogin() {
+	authInfo, err := c.login()
+	if err != nil {
+		c.l.Error("unable to login with new credentials", slog.String(loggingKeyError, err.Error()))
+		if c.onAuthError != nil {
+			c.onAuthError(err)
+		}
+		return
+	}
+
+	// An auth chain may now have logged in with a method whose token is renewed.
+	if err := c.startAuthRenewal(authInfo); err != nil {
+		c.l.Debug("unable to start auth renewal", slog.String(loggingKeyError, err.Error()))
+	}
+}
+
+// setNamespace sends the requests of the Vault client to the given namespace, or to the root namespace when it is empty.
+func setNamespace(vc *hashiVault.Client, namespace string) {
+	if namespace == "" {
+		vc.ClearNamespace()
+		return
+	}
+
+	vc.SetNamespace(namespace)
+}
+
+// login logs in to Vault on a copy of the Vault client and then swaps the new token into the shared client.
+// Requests in flight therefore see either the old or the new token, never a partially logged in client.
+func (c *client) login() (*hashiVault.Secret, error) {
+	lc, err := c.v.CloneWithHeaders()
+	if err != nil {
+		return nil, fmt.Errorf("unable to clone vault client for login: %w", err)
+	}
+	lc.ClearToken()
+	if c.loginNamespace != nil {
+		setNamespace(lc, *c.loginNamespace)
+	}
 
-		return authInfo, nil
-	})
-	if err != nil { // nolint:revive // Traditional error handling
-		c.l.Error("unable to renew auth info", slog.String(loggingKeyError, err.Error()))
-		os.Exit(1)
+	authInfo, err := c.auth.Login(c.ctx, lc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to login with %s auth method: %w", c.auth.Name(), err)
 	}
+
+	if err := c.setAuth(authInfo); err != nil {
+		return nil, err
+	}
+
+	return authInfo, nil
+}
+
+// setAuth sets the token of the Vault client and the auth info it came from.
+func (c *client) setAuth(authInfo *hashiVault.Secret) error {
+	if authInfo == nil {
+		return errors.New("no auth info was returned after login")
+	}
+
+	token, err := authInfo.TokenID()
+	if err != nil {
+		return fmt.Errorf("unable to read token from auth info: %w", err)
+	} else if token == "" {
+		return errors.New("no token was returned after login")
+	}
+
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	c.v.SetToken(token)
+	c.authCreds = authInfo
+
+	return nil
+}
+
+// authInfo returns the auth info of the current token.
+func (c *client) authInfo() *hashiVault.Secret {
+	c.authMu.RLock()
+	defer c.authMu.RUnlock()
+	return c.authCreds
+}
+
+// withNamespace returns a copy of the Vault client that sends its requests to the given namespace.
+// The copy is taken under the auth lock, so it never races with a new token being swapped in.
+func (c *client) withNamespace(namespace string) *hashiVault.Client {
+	c.authMu.RLock()
+	defer c.authMu.RUnlock()
+	return c.v.WithNamespace(namespace)
 }
 
 // Client returns the Vault client.
@@ -0,0 +1,401 @@
+package vaulty
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	hashiVault "github.com/hashicorp/vault/api"
+	"github.com/spf13/viper"
+)
+
+// ClientConfig is the declarative configuration of a client.
+// It is read from a YAML or JSON file with LoadClientConfig, or from a viper sub-tree with ClientConfigFromViper,
+// which both accept durations as strings such as "30s". Decoding it directly with encoding/json does not.
+type ClientConfig struct {
+	// Address is the address of the Vault server. Defaults to VAULT_ADDR.
+	Address string `yaml:"address" json:"address" mapstructure:"address"`
+
+	// Namespace is the Vault Enterprise namespace of the client. Defaults to VAULT_NAMESPACE; an empty namespace is the root namespace.
+	Namespace *string `yaml:"namespace" json:"namespace" mapstructure:"namespace"`
+
+	// TLS configures the connection to the Vault server.
+	TLS TLSConfig `yaml:"tls" json:"tls" mapstructure:"tls"`
+
+	// Auth configures how the client logs in to Vault.
+	Auth AuthConfig `yaml:"auth" json:"auth" mapstructure:"auth"`
+
+	// Kvv2Mount is the KVv2 mount used by Path.
+	Kvv2Mount string `yaml:"kvv2_mount" json:"kvv2_mount" mapstructure:"kvv2_mount"`
+
+	// Retry configures how logins are retried once the token can no longer be renewed.
+	Retry RetryConfig `yaml:"retry" json:"retry" mapstructure:"retry"`
+
+	// Renewal configures the renewal of the token and the leases of the client.
+	Renewal RenewalConfig `yaml:"renewal" json:"renewal" mapstructure:"renewal"`
+}
+
+// TLSConfig configures the TLS connection to the Vault server.
+type TLSConfig struct {
+	CACert     string `yaml:"ca_cert" json:"ca_cert" mapstructure:"ca_cert"`
+	CAPath     string `yaml:"ca_path" json:"ca_path" mapstructure:"ca_path"`
+	ClientCert string `yaml:"client_cert" json:"client_cert" mapstructure:"client_cert"`
+	ClientKey  string `yaml:"client_key" json:"client_key" mapstructure:"client_key"`
+	ServerName string `yaml:"server_name" json:"server_name" mapstructure:"server_name"`
+	Insecure   bool   `yaml:"insecure" json:"insecure" mapstructure:"insecure"`
+}
+
+// AuthConfig configures the auth method of the client.
+// Only the parameters of the selected method are used.
+type AuthConfig struct {
+	// Method is one of token, token_file, approle, userpass, ldap, kubernetes, jwt, cert or aws.
+	Method string `yaml:"method" json:"method" mapstructure:"method"`
+
+	// Mount is the path the auth method is mounted at. Defaults to the name of the method.
+	Mount string `yaml:"mount" json:"mount" mapstructure:"mount"`
+
+	// Namespace is the Vault Enterprise namespace to log in to, when it differs from the namespace of the client.
+	// An empty namespace is the root namespace.
+	Namespace *string `yaml:"namespace" json:"namespace" mapstructure:"namespace"`
+
+	// Role is the role of the kubernetes, jwt, cert and aws auth methods.
+	Role string `yaml:"role" json:"role" mapstructure:"role"`
+
+	// LoginTimeout is how long a single login request may take.
+	LoginTimeout time.Duration `yaml:"login_timeout" json:"login_timeout" mapstructure:"login_timeout"`
+
+	// Token settings
+	Token     string `yaml:"token" json:"token" mapstructure:"token"`
+	TokenFile string `yaml:"token_file" json:"token_file" mapstructure:"token_file"`
+
+	// AppRole settings
+	RoleID          string `yaml:"role_id" json:"role_id" mapstructure:"role_id"`
+	SecretID        string `yaml:"secret_id" json:"secret_id" mapstructure:"secret_id"`
+	SecretIDFile    string `yaml:"secret_id_file" json:"secret_id_file" mapstructure:"secret_id_file"`
+	WrappedSecretID bool   `yaml:"wrapped_secret_id" json:"wrapped_secret_id" mapstructure:"wrapped_secret_id"`
+
+	// UserPass and LDAP settings
+	Username     string `yaml:"username" json:"username" mapstructure:"username"`
+	Password     string `yaml:"password" json:"password" mapstructure:"password"`
+	PasswordFile string `yaml:"password_file" json:"password_file" mapstructure:"password_file"`
+
+	// JWT settings
+	JWTFile string `yaml:"jwt_file" json:"jwt_file" mapstructure:"jwt_file"`
+
+	// AWS IAM settings
+	AWSRegion   string `yaml:"aws_region" json:"aws_region" mapstructure:"aws_region"`
+	AWSServerID string `yaml:"aws_server_id" json:"aws_server_id" mapstructure:"aws_server_id"`
+}
+
+// RetryConfig configures the login retry policy. Unset fields keep the values of DefaultRetryPolicy.
+// Jitter is a pointer so that it can be turned off by setting it to 0.
+type RetryConfig struct {
+	InitialInterval time.Duration `yaml:"initial_interval" json:"initial_interval" mapstructure:"initial_interval"`
+	MaxInterval     time.Duration `yaml:"max_interval" json:"max_interval" mapstructure:"max_interval"`
+	Multiplier      float64       `yaml:"multiplier" json:"multiplier" mapstructure:"multiplier"`
+	Jitter          *float64      `yaml:"jitter" json:"jitter" mapstructure:"jitter"`
+	MaxAttempts     int           `yaml:"max_attempts" json:"max_attempts" mapstructure:"max_attempts"`
+	MaxElapsedTime  time.Duration `yaml:"max_elapsed_time" json:"max_elapsed_time" mapstructure:"max_elapsed_time"`
+}
+
+// RenewalConfig configures the renewal of the token and the leases of the client.
+type RenewalConfig struct {
+	// RevokeOnClose makes Close revoke the leases renewed through the client and then the client token itself.
+	RevokeOnClose bool `yaml:"revoke_on_close" json:"revoke_on_close" mapstructure:"revoke_on_close"`
+
+	// PollInterval is how often credential files, such as a Vault Agent token sink, are checked for changes.
+	PollInterval time.Duration `yaml:"poll_interval" json:"poll_interval" mapstructure:"poll_interval"`
+}
+
+// FieldError is returned when a field of a ClientConfig is invalid.
+type FieldError struct {
+	// Field is the path of the field, such as "auth.role_id".
+	Field string
+
+	// Message describes what is wrong with the field.
+	Message string
+}
+
+// Error returns the field and what is wrong with it.
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ClientConfigFromViper reads a ClientConfig from the given viper instance, such as a sub-tree returned by viper.Sub.
+// Durations may be given as strings, such as "30s".
+func ClientConfigFromViper(v *viper.Viper) (*ClientConfig, error) {
+	if v == nil {
+		return nil, errors.New("no viper configuration provided")
+	}
+
+	cfg := new(ClientConfig)
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal client config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// LoadClientConfig reads a ClientConfig from the given YAML or JSON file, detected by its extension.
+func LoadClientConfig(path string) (*ClientConfig, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("unable to read client config: %w", err)
+	}
+
+	return ClientConfigFromViper(v)
+}
+
+// NewClientFromConfig creates a new Vault client from the given config.
+// The config is validated first; the returned error lists every invalid field.
+// The given options are applied after the config, so they override it.
+func NewClientFromConfig(cfg *ClientConfig, opts ...ClientOption) (Client, error) {
+	if cfg == nil {
+		return nil, errors.New("client config is nil")
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid client config: %w", err)
+	}
+
+	cfgOpts, err := cfg.clientOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(append(cfgOpts, opts...)...)
+}
+
+// Validate checks the config, returning a FieldError for every invalid field.
+func (cfg *ClientConfig) Validate() error {
+	var errs []error
+	invalid := func(field, format string, args ...any) {
+		errs = append(errs, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if cfg.Address != "" {
+		if u, err := url.Parse(cfg.Address); err != nil || u.Scheme == "" || u.Host == "" {
+			invalid("address", "must be an absolute URL, such as https://vault.example.com:8200")
+		}
+	}
+
+	if (cfg.TLS.ClientCert == "") != (cfg.TLS.ClientKey == "") {
+		invalid("tls", "client_cert and client_key must be set together")
+	}
+
+	cfg.Auth.validate(cfg.TLS.ClientCert, invalid)
+
+	if cfg.Retry.InitialInterval < 0 {
+		invalid("retry.initial_interval", "must not be negative")
+	}
+	if cfg.Retry.MaxInterval < 0 {
+		invalid("retry.max_interval", "must not be negative")
+	}
+	if cfg.Retry.Multiplier < 0 {
+		invalid("retry.multiplier", "must not be negative")
+	}
+	if j := cfg.Retry.Jitter; j != nil && (*j < 0 || *j > 1) {
+		invalid("retry.jitter", "must be between 0 and 1")
+	}
+	if cfg.Retry.MaxAttempts < 0 {
+		invalid("retry.max_attempts", "must not be negative")
+	}
+	if cfg.Retry.MaxElapsedTime < 0 {
+		invalid("retry.max_elapsed_time", "must not be negative")
+	}
+	if cfg.Renewal.PollInterval < 0 {
+		invalid("renewal.poll_interval", "must not be negative")
+	}
+
+	return errors.Join(errs...)
+}
+
+// validate checks the parameters of the selected auth method. clientCert is the client certificate of the TLS config,
+// which cert auth presents.
+func (a *AuthConfig) validate(clientCert string, invalid func(field, format string, args ...any)) {
+	required := func(field, value string) {
+		if value == "" {
+			invalid("auth."+field, "is required for %s auth", a.Method)
+		}
+	}
+	oneOf := func(field, value, otherField, otherValue string) {
+		if value == "" && otherValue == "" {
+			invalid("auth."+field, "or auth.%s is required for %s auth", otherField, a.Method)
+		} else if value != "" && otherValue != "" {
+			invalid("auth."+field, "must not be set together with auth.%s", otherField)
+		}
+	}
+
+	if a.LoginTimeout < 0 {
+		invalid("auth.login_timeout", "must not be negative")
+	}
+
+	switch a.Method {
+	case "":
+		invalid("auth.method", "is required")
+	case authNameToken:
+		required("token", a.Token)
+	case authNameTokenFile:
+		required("token_file", a.TokenFile)
+	case authNameAppRole:
+		required("role_id", a.RoleID)
+		oneOf("secret_id", a.SecretID, "secret_id_file", a.SecretIDFile)
+	case authNameUserPass, authNameLDAP:
+		required("username", a.Username)
+		oneOf("password", a.Password, "password_file", a.PasswordFile)
+	case authNameKubernetes, authNameAWS:
+		required("role", a.Role)
+	case authNameJWT:
+		required("role", a.Role)
+		required("jwt_file", a.JWTFile)
+	case authNameCert:
+		if clientCert == "" {
+			invalid("tls.client_cert", "is required for cert auth")
+		}
+	default:
+		invalid("auth.method", "unsupported auth method %q", a.Method)
+	}
+}
+
+// clientOptions returns the client options that apply the config.
+func (cfg *ClientConfig) clientOptions() ([]ClientOption, error) {
+	config := hashiVault.DefaultConfig()
+	if config.Error != nil {
+		return nil, fmt.Errorf("unable to read vault environment: %w", config.Error)
+	}
+
+	if cfg.Address != "" {
+		config.Address = cfg.Address
+	}
+
+	if cfg.TLS != (TLSConfig{}) {
+		if err := config.ConfigureTLS(&hashiVault.TLSConfig{
+			CACert:        cfg.TLS.CACert,
+			CAPath:        cfg.TLS.CAPath,
+			ClientCert:    cfg.TLS.ClientCert,
+			ClientKey:     cfg.TLS.ClientKey,
+			TLSServerName: cfg.TLS.ServerName,
+			Insecure:      cfg.TLS.Insecure,
+		}); err != nil {
+			return nil, fmt.Errorf("unable to configure TLS: %w", err)
+		}
+	}
+
+	method, err := cfg.authMethod()
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure auth method: %w", err)
+	}
+
+	opts := []ClientOption{
+		WithConfig(config),
+		WithAuthMethod(method),
+		WithAuthRetryPolicy(cfg.Retry.policy()),
+	}
+
+	if cfg.Namespace != nil {
+		opts = append(opts, WithNamespace(*cfg.Namespace))
+	}
+
+	if cfg.Auth.Namespace != nil {
+		opts = append(opts, WithLoginNamespace(*cfg.Auth.Namespace))
+	}
+
+	if cfg.Kvv2Mount != "" {
+		opts = append(opts, WithKvv2Mount(cfg.Kvv2Mount))
+	}
+
+	if cfg.Renewal.RevokeOnClose {
+		opts = append(opts, WithRevokeOnClose())
+	}
+
+	return opts, nil
+}
+
+// authMethod returns the auth method selected by the config.
+func (cfg *ClientConfig) authMethod() (AuthMethod, error) {
+	a := cfg.Auth
+
+	var opts []AuthOption
+	if a.Mount != "" {
+		opts = append(opts, WithAuthMount(a.Mount))
+	}
+	if a.LoginTimeout > 0 {
+		opts = append(opts, WithLoginTimeout(a.LoginTimeout))
+	}
+	if cfg.Renewal.PollInterval > 0 {
+		opts = append(opts, WithPollInterval(cfg.Renewal.PollInterval))
+	}
+
+	switch a.Method {
+	case authNameToken:
+		return NewTokenAuth(a.Token, opts...)
+	case authNameTokenFile:
+		return NewTokenFileAuth(a.TokenFile, opts...)
+	case authNameAppRole:
+		secretID := CredentialFromString(a.SecretID)
+		if a.SecretIDFile != "" {
+			secretID = CredentialFromFile(a.SecretIDFile)
+		}
+		if a.WrappedSecretID {
+			opts = append(opts, WithWrappedSecretID())
+		}
+		return NewAppRoleAuthFromSource(a.RoleID, secretID, opts...)
+	case authNameUserPass:
+		password, err := a.password()()
+		if err != nil {
+			return nil, err
+		}
+		return NewUserPassAuth(a.Username, password, opts...)
+	case authNameLDAP:
+		return NewLDAPAuth(a.Username, a.password(), opts...)
+	case authNameKubernetes:
+		return NewKubernetesServiceAccountAuth(a.Role, opts...)
+	case authNameJWT:
+		return NewJWTAuthFromFile(a.Role, a.JWTFile, opts...)
+	case authNameCert:
+		if a.Role != "" {
+			opts = append(opts, WithCertRole(a.Role))
+		}
+		return NewCertAuth(cfg.TLS.ClientCert, cfg.TLS.ClientKey, opts...)
+	case authNameAWS:
+		if a.AWSRegion != "" {
+			opts = append(opts, WithAWSRegion(a.AWSRegion))
+		}
+		if a.AWSServerID != "" {
+			opts = append(opts, WithAWSServerID(a.AWSServerID))
+		}
+		return NewAWSIAMAuth(a.Role, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported auth method %q", a.Method)
+	}
+}
+
+// password returns the source of the userpass or ldap password.
+func (a *AuthConfig) password() CredentialSource {
+	if a.PasswordFile != "" {
+		return CredentialFromFile(a.PasswordFile)
+	}
+	return CredentialFromString(a.Password)
+}
+
+// policy returns the retry policy with the configured fields applied over the defaults.
+func (r *RetryConfig) policy() *RetryPolicy {
+	p := DefaultRetryPolicy()
+	if r.InitialInterval > 0 {
+		p.InitialInterval = r.InitialInterval
+	}
+	if r.MaxInterval > 0 {
+		p.MaxInterval = r.MaxInterval
+	}
+	if r.Multiplier > 0 {
+		p.Multiplier = r.Multiplier
+	}
+	if r.Jitter != nil {
+		p.Jitter = *r.Jitter
+	}
+	p.MaxAttempts = r.MaxAttempts
+	p.MaxElapsedTime = r.MaxElapsedTime
+	return p
+}
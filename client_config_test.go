@@ -0,0 +1,273 @@
+package vaulty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+const testClientConfigYAML = `
+vault:
+  address: https://vault.example.com:8200
+  namespace: team-a
+  auth:
+    method: approle
+    mount: approle-ci
+    login_timeout: 10s
+    role_id: role-id
+    secret_id_file: /run/secrets/secret-id
+    wrapped_secret_id: true
+  kvv2_mount: team-secrets
+  retry:
+    initial_interval: 2s
+    jitter: 0
+    max_attempts: 5
+  renewal:
+    revoke_on_close: true
+`
+
+// testClientConfig is the config described by testClientConfigYAML.
+func testClientConfig() *ClientConfig {
+	jitter := 0.0
+	namespace := "team-a"
+	return &ClientConfig{
+		Address:   "https://vault.example.com:8200",
+		Namespace: &namespace,
+		Auth: AuthConfig{
+			Method:          "approle",
+			Mount:           "approle-ci",
+			LoginTimeout:    10 * time.Second,
+			RoleID:          "role-id",
+			SecretIDFile:    "/run/secrets/secret-id",
+			WrappedSecretID: true,
+		},
+		Kvv2Mount: "team-secrets",
+		Retry: RetryConfig{
+			InitialInterval: 2 * time.Second,
+			Jitter:          &jitter,
+			MaxAttempts:     5,
+		},
+		Renewal: RenewalConfig{
+			RevokeOnClose: true,
+		},
+	}
+}
+
+func TestClientConfigFromViper(t *testing.T) {
+	t.Parallel()
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(bytes.NewBufferString(testClientConfigYAML)))
+
+	cfg, err := ClientConfigFromViper(v.Sub("vault"))
+	require.NoError(t, err)
+	require.Equal(t, testClientConfig(), cfg)
+
+	_, err = ClientConfigFromViper(nil)
+	require.EqualError(t, err, "no viper configuration provided")
+}
+
+func TestLoadClientConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	yamlFile := filepath.Join(dir, "vault.yaml")
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(bytes.NewBufferString(testClientConfigYAML)))
+	require.NoError(t, v.Sub("vault").WriteConfigAs(yamlFile))
+
+	jsonFile := filepath.Join(dir, "vault.json")
+	require.NoError(t, os.WriteFile(jsonFile, []byte(`{
+		"address": "https://vault.example.com:8200",
+		"namespace": "team-a",
+		"auth": {
+			"method": "approle",
+			"mount": "approle-ci",
+			"login_timeout": "10s",
+			"role_id": "role-id",
+			"secret_id_file": "/run/secrets/secret-id",
+			"wrapped_secret_id": true
+		},
+		"kvv2_mount": "team-secrets",
+		"retry": {"initial_interval": "2s", "jitter": 0, "max_attempts": 5},
+		"renewal": {"revoke_on_close": true}
+	}`), 0o600))
+
+	for _, path := range []string{yamlFile, jsonFile} {
+		t.Run(filepath.Ext(path), func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := LoadClientConfig(path)
+			require.NoError(t, err)
+			require.Equal(t, testClientConfig(), cfg)
+		})
+	}
+
+	_, err := LoadClientConfig(filepath.Join(dir, "missing.yaml"))
+	require.ErrorContains(t, err, "unable to read client config")
+}
+
+func TestClientConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	invalidJitter := 2.0
+	tests := []struct {
+		name   string
+		cfg    ClientConfig
+		fields []string
+	}{
+		{
+			name:   "valid",
+			cfg:    *testClientConfig(),
+			fields: nil,
+		},
+		{
+			name:   "missing auth method",
+			cfg:    ClientConfig{},
+			fields: []string{"auth.method"},
+		},
+		{
+			name:   "unsupported auth method",
+			cfg:    ClientConfig{Auth: AuthConfig{Method: "github"}},
+			fields: []string{"auth.method"},
+		},
+		{
+			name:   "approle without credentials",
+			cfg:    ClientConfig{Auth: AuthConfig{Method: "approle"}},
+			fields: []string{"auth.role_id", "auth.secret_id"},
+		},
+		{
+			name: "userpass with two password sources",
+			cfg: ClientConfig{Auth: AuthConfig{
+				Method: "userpass", Username: "user", Password: "pass", PasswordFile: "/run/secrets/password",
+			}},
+			fields: []string{"auth.password"},
+		},
+		{
+			name:   "cert without client certificate",
+			cfg:    ClientConfig{Auth: AuthConfig{Method: "cert"}},
+			fields: []string{"tls.client_cert"},
+		},
+		{
+			name: "invalid connection and retry settings",
+			cfg: ClientConfig{
+				Address: "vault.example.com",
+				TLS:     TLSConfig{ClientCert: "/etc/vault/client.pem"},
+				Auth:    AuthConfig{Method: "kubernetes", Role: "app", LoginTimeout: -time.Second},
+				Retry:   RetryConfig{Jitter: &invalidJitter, MaxAttempts: -1},
+			},
+			fields: []string{"address", "tls", "auth.login_timeout", "retry.jitter", "retry.max_attempts"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.cfg.Validate()
+			if tt.fields == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			joined, ok := err.(interface{ Unwrap() []error })
+			require.True(t, ok)
+
+			var fields []string
+			for _, err := range joined.Unwrap() {
+				var fieldErr *FieldError
+				require.True(t, errors.As(err, &fieldErr))
+				fields = append(fields, fieldErr.Field)
+			}
+			require.Equal(t, tt.fields, fields)
+		})
+	}
+}
+
+func TestNewClientFromConfig(t *testing.T) {
+	t.Parallel()
+
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("pass\n"), 0o600))
+
+	var (
+		body      map[string]any
+		namespace string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/corp-ldap/login/jdoe", func(w http.ResponseWriter, r *http.Request) {
+		namespace = r.Header.Get("X-Vault-Namespace")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		writeTestJSON(t, w, longLivedAuthResponse("ldap-token"))
+	})
+	mux.HandleFunc("PUT /v1/auth/token/revoke-self", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	srv := newTestVaultServer(t, mux)
+
+	clientNamespace := "team-a"
+	vc, err := NewClientFromConfig(&ClientConfig{
+		Address:   srv.URL,
+		Namespace: &clientNamespace,
+		Auth: AuthConfig{
+			Method:       "ldap",
+			Mount:        "corp-ldap",
+			Username:     "jdoe",
+			PasswordFile: passwordFile,
+		},
+		Kvv2Mount: "team-secrets",
+		Retry: RetryConfig{
+			MaxAttempts: 3,
+		},
+		Renewal: RenewalConfig{
+			RevokeOnClose: true,
+		},
+	}, WithLogger(slog.New(slog.DiscardHandler)), WithKvv2Mount("explicit"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	c, ok := vc.(*client)
+	require.True(t, ok)
+	require.Equal(t, "ldap-token", vc.Client().Token())
+	require.Equal(t, map[string]any{"password": "pass"}, body)
+	require.Equal(t, "team-a", namespace)
+	require.Equal(t, "explicit", c.kvv2Mount)
+	require.Equal(t, 3, c.retryPolicy.MaxAttempts)
+	require.Equal(t, time.Second, c.retryPolicy.InitialInterval)
+	require.InDelta(t, DefaultRetryPolicy().Jitter, c.retryPolicy.Jitter, 0)
+	require.True(t, c.revokeOnClose)
+}
+
+func TestRetryConfig_Policy(t *testing.T) {
+	t.Parallel()
+
+	require.InDelta(t, DefaultRetryPolicy().Jitter, (&RetryConfig{}).policy().Jitter, 0)
+
+	jitter := 0.0
+	require.Zero(t, (&RetryConfig{Jitter: &jitter}).policy().Jitter)
+}
+
+func TestNewClientFromConfig_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewClientFromConfig(&ClientConfig{Auth: AuthConfig{Method: "jwt"}})
+	require.EqualError(t, err, "invalid client config: auth.role: is required for jwt auth\nauth.jwt_file: is required for jwt auth")
+
+	_, err = NewClientFromConfig(nil)
+	require.EqualError(t, err, "client config is nil")
+}
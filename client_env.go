@@ -0,0 +1,205 @@
+package vaulty
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	hashiVault "github.com/hashicorp/vault/api"
+)
+
+// NewClientFromEnv creates a new Vault client configured from environment variables.
+//
+// The standard Vault variables, such as VAULT_ADDR, VAULT_TOKEN, VAULT_NAMESPACE and VAULT_CACERT,
+// are read by the Vault API client. The vaulty variables select the auth method and its parameters:
+//
+//   - VAULTY_AUTH_METHOD: one of token, token_file, approle, userpass, ldap, kubernetes, jwt, cert or aws.
+//     Defaults to token when VAULT_TOKEN is set.
+//   - VAULTY_AUTH_MOUNT: the path the auth method is mounted at.
+//   - VAULTY_AUTH_NAMESPACE: the namespace to log in to, when it differs from VAULT_NAMESPACE.
+//     Set it to an empty value to log in to the root namespace.
+//   - VAULTY_AUTH_ROLE: the role of the kubernetes, jwt, cert and aws auth methods.
+//     The kubernetes auth method falls back to SERVICE_ACCOUNT_NAME.
+//   - VAULTY_TOKEN_FILE: the token file of the token_file auth method.
+//   - VAULTY_APPROLE_ROLE_ID, and VAULTY_APPROLE_SECRET_ID or VAULTY_APPROLE_SECRET_ID_FILE: the AppRole credentials.
+//     Set VAULTY_APPROLE_SECRET_ID_WRAPPED to true when the secret ID is a response-wrapping token.
+//   - VAULTY_USERNAME and VAULTY_PASSWORD: the credentials of the userpass and ldap auth methods.
+//   - VAULTY_JWT_FILE: the file holding the JWT of the jwt auth method.
+//   - VAULT_CLIENT_CERT and VAULT_CLIENT_KEY: the client certificate of the cert auth method.
+//   - VAULTY_AWS_REGION: the STS region of the aws auth method.
+//   - VAULTY_KVV2_MOUNT: the KVv2 mount used by Path.
+//
+// The given options are applied after the environment, so they override it. The auth method is only read from
+// the environment when no option sets one, so an incomplete environment does not fail an explicitly configured client.
+func NewClientFromEnv(opts ...ClientOption) (Client, error) {
+	opts = append([]ClientOption{withEnv()}, opts...)
+	return NewClient(append(opts, withEnvAuth())...)
+}
+
+// withEnv configures the client from the vaulty environment variables, except for the auth method.
+func withEnv() ClientOption {
+	return func(c *client) error {
+		if mount := os.Getenv(envKvv2Mount); mount != "" {
+			c.kvv2Mount = mount
+		}
+
+		if namespace, ok := os.LookupEnv(envAuthNamespace); ok {
+			c.loginNamespace = &namespace
+		}
+
+		return nil
+	}
+}
+
+// withEnvAuth sets the auth method selected by the environment, unless an auth method is already set.
+func withEnvAuth() ClientOption {
+	return func(c *client) error {
+		if c.auth != nil {
+			return nil
+		}
+
+		name := authMethodNameFromEnv()
+		if name == "" {
+			return nil
+		}
+
+		method, err := authMethodFromEnv(name)
+		if err != nil {
+			return fmt.Errorf("unable to configure auth method from environment: %w", err)
+		}
+
+		return WithAuthMethod(method)(c)
+	}
+}
+
+// authMethodNameFromEnv returns the name of the auth method selected by the environment, if any.
+func authMethodNameFromEnv() string {
+	if name := os.Getenv(envAuthMethod); name != "" {
+		return name
+	} else if os.Getenv(hashiVault.EnvVaultToken) != "" {
+		return authNameToken
+	}
+	return ""
+}
+
+// authMethodFromEnv returns the auth method with the given name, configured by the environment.
+func authMethodFromEnv(name string) (AuthMethod, error) {
+	var opts []AuthOption
+	if mount := os.Getenv(envAuthMount); mount != "" {
+		opts = append(opts, WithAuthMount(mount))
+	}
+
+	switch name {
+	case authNameToken:
+		token, err := requiredEnv(hashiVault.EnvVaultToken)
+		if err != nil {
+			return nil, err
+		}
+		return NewTokenAuth(token, opts...)
+	case authNameTokenFile:
+		path, err := requiredEnv(envTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		return NewTokenFileAuth(path, opts...)
+	case authNameAppRole:
+		return appRoleAuthFromEnv(opts)
+	case authNameUserPass:
+		username, err := requiredEnv(envUsername)
+		if err != nil {
+			return nil, err
+		}
+		password, err := requiredEnv(envPassword)
+		if err != nil {
+			return nil, err
+		}
+		return NewUserPassAuth(username, password, opts...)
+	case authNameLDAP:
+		username, err := requiredEnv(envUsername)
+		if err != nil {
+			return nil, err
+		}
+		return NewLDAPAuth(username, CredentialFromEnv(envPassword), opts...)
+	case authNameKubernetes:
+		role := os.Getenv(envAuthRole)
+		if role == "" {
+			role = os.Getenv(envServiceAccountName)
+		}
+		if role == "" {
+			return nil, fmt.Errorf("environment variable %s or %s is not set", envAuthRole, envServiceAccountName)
+		}
+		return NewKubernetesServiceAccountAuth(role, opts...)
+	case authNameJWT:
+		role, err := requiredEnv(envAuthRole)
+		if err != nil {
+			return nil, err
+		}
+		path, err := requiredEnv(envJWTFile)
+		if err != nil {
+			return nil, err
+		}
+		return NewJWTAuthFromFile(role, path, opts...)
+	case authNameCert:
+		certFile, err := requiredEnv(hashiVault.EnvVaultClientCert)
+		if err != nil {
+			return nil, err
+		}
+		keyFile, err := requiredEnv(hashiVault.EnvVaultClientKey)
+		if err != nil {
+			return nil, err
+		}
+		if role := os.Getenv(envAuthRole); role != "" {
+			opts = append(opts, WithCertRole(role))
+		}
+		return NewCertAuth(certFile, keyFile, opts...)
+	case authNameAWS:
+		role, err := requiredEnv(envAuthRole)
+		if err != nil {
+			return nil, err
+		}
+		if region := os.Getenv(envAWSRegion); region != "" {
+			opts = append(opts, WithAWSRegion(region))
+		}
+		return NewAWSIAMAuth(role, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported auth method %q in environment variable %s", name, envAuthMethod)
+	}
+}
+
+// appRoleAuthFromEnv returns the AppRole auth method configured by the environment.
+func appRoleAuthFromEnv(opts []AuthOption) (AuthMethod, error) {
+	roleID, err := requiredEnv(envAppRoleRoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	var secretID CredentialSource
+	switch {
+	case os.Getenv(envAppRoleSecretID) != "":
+		secretID = CredentialFromEnv(envAppRoleSecretID)
+	case os.Getenv(envAppRoleSecretIDFile) != "":
+		secretID = CredentialFromFile(os.Getenv(envAppRoleSecretIDFile))
+	default:
+		return nil, fmt.Errorf("environment variable %s or %s is not set", envAppRoleSecretID, envAppRoleSecretIDFile)
+	}
+
+	if v := os.Getenv(envAppRoleSecretIDWrapped); v != "" {
+		wrapped, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse environment variable %s: %w", envAppRoleSecretIDWrapped, err)
+		} else if wrapped {
+			opts = append(opts, WithWrappedSecretID())
+		}
+	}
+
+	return NewAppRoleAuthFromSource(roleID, secretID, opts...)
+}
+
+// requiredEnv returns the value of the given environment variable, or an error if it is not set.
+func requiredEnv(name string) (string, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
@@ -0,0 +1,232 @@
+package vaulty
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	hashiVault "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/require"
+)
+
+// setTestEnv unsets the environment variables read by NewClientFromEnv and then sets the given ones.
+func setTestEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+
+	for _, name := range []string{
+		hashiVault.EnvVaultAddress, hashiVault.EnvVaultToken, hashiVault.EnvVaultNamespace,
+		envAuthMethod, envAuthMount, envAuthNamespace, envAuthRole, envTokenFile,
+		envAppRoleRoleID, envAppRoleSecretID, envAppRoleSecretIDFile, envAppRoleSecretIDWrapped,
+		envUsername, envPassword, envJWTFile, envAWSRegion, envKvv2Mount, envServiceAccountName,
+	} {
+		t.Setenv(name, "")
+		require.NoError(t, os.Unsetenv(name))
+	}
+
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+}
+
+func TestNewClientFromEnv_AppRole(t *testing.T) {
+	secretIDFile := filepath.Join(t.TempDir(), "secret-id")
+	require.NoError(t, os.WriteFile(secretIDFile, []byte("secret-id\n"), 0o600))
+
+	var (
+		body      map[string]any
+		namespace string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/approle-ci/login", func(w http.ResponseWriter, r *http.Request) {
+		namespace = r.Header.Get("X-Vault-Namespace")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		writeTestJSON(t, w, longLivedAuthResponse("approle-token"))
+	})
+	srv := newTestVaultServer(t, mux)
+
+	setTestEnv(t, map[string]string{
+		hashiVault.EnvVaultAddress:   srv.URL,
+		hashiVault.EnvVaultNamespace: "team-a",
+		envAuthMethod:                "approle",
+		envAuthMount:                 "approle-ci",
+		envAppRoleRoleID:             "role-id",
+		envAppRoleSecretIDFile:       secretIDFile,
+		envKvv2Mount:                 "team-secrets",
+	})
+
+	vc, err := NewClientFromEnv(WithLogger(slog.New(slog.DiscardHandler)))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	require.Equal(t, "approle-token", vc.Client().Token())
+	require.Equal(t, map[string]any{"role_id": "role-id", "secret_id": "secret-id"}, body)
+	require.Equal(t, "team-a", namespace)
+	path, ok := vc.Path("app").(*SecretPath)
+	require.True(t, ok)
+	require.Equal(t, "team-secrets", path.mount)
+}
+
+func TestNewClientFromEnv_RootLoginNamespace(t *testing.T) {
+	var namespace string
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/userpass/login/user", func(w http.ResponseWriter, r *http.Request) {
+		namespace = r.Header.Get("X-Vault-Namespace")
+		writeTestJSON(t, w, longLivedAuthResponse("userpass-token"))
+	})
+	srv := newTestVaultServer(t, mux)
+
+	setTestEnv(t, map[string]string{
+		hashiVault.EnvVaultAddress:   srv.URL,
+		hashiVault.EnvVaultNamespace: "team-a",
+		envAuthMethod:                "userpass",
+		envAuthNamespace:             "",
+		envUsername:                  "user",
+		envPassword:                  "pass",
+	})
+
+	vc, err := NewClientFromEnv(WithLogger(slog.New(slog.DiscardHandler)))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	require.Empty(t, namespace)
+	require.Equal(t, "team-a", vc.Client().Namespace())
+}
+
+func TestNewClientFromEnv_VaultToken(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/auth/token/lookup-self", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "env-token", r.Header.Get("X-Vault-Token"))
+		writeTestJSON(t, w, tokenLookupResponse("env-token", false, 0))
+	})
+	srv := newTestVaultServer(t, mux)
+
+	setTestEnv(t, map[string]string{
+		hashiVault.EnvVaultAddress: srv.URL,
+		hashiVault.EnvVaultToken:   "env-token",
+	})
+
+	vc, err := NewClientFromEnv(WithLogger(slog.New(slog.DiscardHandler)))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	require.Equal(t, "env-token", vc.Client().Token())
+	c, ok := vc.(*client)
+	require.True(t, ok)
+	require.Equal(t, authNameToken, c.auth.Name())
+}
+
+func TestNewClientFromEnv_OptionsOverrideEnv(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/userpass/login/explicit", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestJSON(t, w, longLivedAuthResponse("userpass-token"))
+	})
+	srv := newTestVaultServer(t, mux)
+
+	setTestEnv(t, map[string]string{
+		hashiVault.EnvVaultAddress: "http://127.0.0.1:0",
+		envAuthMethod:              "userpass",
+		envUsername:                "from-env",
+		envPassword:                "password",
+		envKvv2Mount:               "from-env",
+	})
+
+	vc, err := NewClientFromEnv(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithUserPassAuth("explicit", "password"),
+		WithKvv2Mount("explicit"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	require.Equal(t, "userpass-token", vc.Client().Token())
+	path, ok := vc.Path("app").(*SecretPath)
+	require.True(t, ok)
+	require.Equal(t, "explicit", path.mount)
+}
+
+func TestNewClientFromEnv_InvalidEnvAuthOverridden(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/userpass/login/explicit", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestJSON(t, w, longLivedAuthResponse("userpass-token"))
+	})
+	srv := newTestVaultServer(t, mux)
+
+	setTestEnv(t, map[string]string{
+		hashiVault.EnvVaultAddress: srv.URL,
+		envAuthMethod:              "jwt",
+	})
+
+	vc, err := NewClientFromEnv(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithUserPassAuth("explicit", "password"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	require.Equal(t, "userpass-token", vc.Client().Token())
+}
+
+func TestNewClientFromEnv_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name:    "no auth method",
+			env:     map[string]string{},
+			wantErr: ErrInvalidAuth.Error(),
+		},
+		{
+			name:    "unsupported auth method",
+			env:     map[string]string{envAuthMethod: "github"},
+			wantErr: `unsupported auth method "github" in environment variable VAULTY_AUTH_METHOD`,
+		},
+		{
+			name:    "missing parameter",
+			env:     map[string]string{envAuthMethod: "jwt", envAuthRole: "ci"},
+			wantErr: "environment variable VAULTY_JWT_FILE is not set",
+		},
+		{
+			name:    "missing kubernetes role",
+			env:     map[string]string{envAuthMethod: "kubernetes"},
+			wantErr: "environment variable VAULTY_AUTH_ROLE or SERVICE_ACCOUNT_NAME is not set",
+		},
+		{
+			name: "invalid wrapped flag",
+			env: map[string]string{
+				envAuthMethod:             "approle",
+				envAppRoleRoleID:          "role-id",
+				envAppRoleSecretID:        "secret-id",
+				envAppRoleSecretIDWrapped: "maybe",
+			},
+			wantErr: "unable to parse environment variable VAULTY_APPROLE_SECRET_ID_WRAPPED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestVaultServer(t, http.NewServeMux())
+			tt.env[hashiVault.EnvVaultAddress] = srv.URL
+			setTestEnv(t, tt.env)
+
+			_, err := NewClientFromEnv(WithLogger(slog.New(slog.DiscardHandler)))
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -2,12 +2,12 @@ package vaulty
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"log/slog"
 	"os"
 
 	hashiVault "github.com/hashicorp/vault/api"
-	kubernetesAuth "github.com/hashicorp/vault/api/auth/kubernetes"
 )
 
 // ClientOption is a function that configures the client.
@@ -29,6 +29,53 @@ func WithLogger(l *slog.Logger) ClientOption {
 	}
 }
 
+// WithAuthRetryPolicy sets the policy used to retry logging in to Vault when the auth token can no longer be renewed.
+// It is also used to retry the renewal functions of leases renewed with RenewLease.
+func WithAuthRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(c *client) error {
+		if policy == nil {
+			return errors.New("retry policy is nil")
+		}
+
+		c.retryPolicy = policy
+		return nil
+	}
+}
+
+// WithOnAuthError sets a function that is called every time logging in to Vault fails while renewing the auth token.
+func WithOnAuthError(fn func(err error)) ClientOption {
+	return func(c *client) error {
+		c.onAuthError = fn
+		return nil
+	}
+}
+
+// WithAuthErrorChannel sets a channel that receives the terminal error when the client gives up renewing its auth token.
+// The error is sent without blocking, so the channel should be buffered.
+func WithAuthErrorChannel(ch chan<- error) ClientOption {
+	return func(c *client) error {
+		c.authErrCh = ch
+		return nil
+	}
+}
+
+// WithOnLeaseError sets a function that is called when the client gives up renewing a lease renewed with RenewLease,
+// such as when its renewal function keeps failing until the retry policy is exhausted.
+func WithOnLeaseError(fn func(name string, err error)) ClientOption {
+	return func(c *client) error {
+		c.onLeaseError = fn
+		return nil
+	}
+}
+
+// WithRevokeOnClose makes Close revoke the leases renewed through the client and then the client token itself.
+func WithRevokeOnClose() ClientOption {
+	return func(c *client) error {
+		c.revokeOnClose = true
+		return nil
+	}
+}
+
 // WithGeneratedVaultClient creates a vault client with the given address.
 //
 // Deprecated: Use WithAddr instead for the same effect.
@@ -52,62 +99,124 @@ func WithConfig(config *hashiVault.Config) ClientOption {
 	}
 }
 
-// WithTokenAuth sets the token for the client.
-func WithTokenAuth(token string) ClientOption {
+// WithNamespace sets the Vault Enterprise namespace the client sends its requests to.
+// It overrides the VAULT_NAMESPACE environment variable; an empty namespace sends the requests to the root namespace.
+func WithNamespace(namespace string) ClientOption {
 	return func(c *client) error {
-		if token == "" {
-			return errors.New("token is empty")
-		}
+		c.namespace = &namespace
+		return nil
+	}
+}
 
-		c.auth = func(v *hashiVault.Client) (*hashiVault.Secret, error) {
-			return tokenLogin(v, token)
-		}
+// WithLoginNamespace sets the Vault Enterprise namespace the client logs in to, when the auth method
+// is enabled in a different namespace than the one the client reads from, such as a parent namespace.
+// An empty namespace logs in to the root namespace.
+func WithLoginNamespace(namespace string) ClientOption {
+	return func(c *client) error {
+		c.loginNamespace = &namespace
 		return nil
 	}
 }
 
-// WithAppRoleAuth sets the AppRole authentication method for the client.
-func WithAppRoleAuth(roleID, secretID string) ClientOption {
+// WithAuthMethod sets the authentication method for the client.
+// Any hashiVault.AuthMethod is accepted, including those from the upstream api/auth packages.
+// Methods that do not implement AuthMethod are named after their type and have their tokens renewed.
+func WithAuthMethod(method hashiVault.AuthMethod) ClientOption {
 	return func(c *client) error {
-		if roleID == "" {
-			return errors.New("roleID is empty")
-		} else if secretID == "" {
-			return errors.New("secretID is empty")
+		if method == nil {
+			return ErrInvalidAuth
 		}
 
-		c.auth = func(v *hashiVault.Client) (*hashiVault.Secret, error) {
-			sec, err := appRoleLogin(v, roleID, secretID)
-			if err != nil {
-				return nil, err
-			}
-			go c.renewAuthInfo()
-			return sec, nil
+		m := asAuthMethod(method)
+		c.auth = m
+		c.clientCert = nil
+		if cm, ok := m.(clientCertMethod); ok {
+			c.clientCert = cm.clientCertificate()
 		}
 		return nil
 	}
 }
 
-// WithUserPassAuth sets the UserPass authentication method for the client.
-func WithUserPassAuth(username, password string) ClientOption {
+// withAuthMethod sets the authentication method returned by one of the built-in constructors.
+func withAuthMethod(method AuthMethod, err error) ClientOption {
 	return func(c *client) error {
-		if username == "" {
-			return errors.New("username is empty")
-		} else if password == "" {
-			return errors.New("password is empty")
+		if err != nil {
+			return err
 		}
 
-		c.auth = func(v *hashiVault.Client) (*hashiVault.Secret, error) {
-			sec, err := userPassLogin(v, username, password)
-			if err != nil {
-				return nil, err
-			}
-			go c.renewAuthInfo()
-			return sec, nil
-		}
-		return nil
+		return WithAuthMethod(method)(c)
 	}
 }
 
+// WithAuthChain sets an ordered list of authentication methods for the client.
+// On every login, including re-logins, the methods are tried in order and the first that succeeds is used.
+// The winning method is logged through the client's logger; if they all fail, the error lists each failure.
+func WithAuthChain(methods ...hashiVault.AuthMethod) ClientOption {
+	return withAuthMethod(NewAuthChain(methods...))
+}
+
+// WithTokenAuth sets the token for the client.
+func WithTokenAuth(token string, opts ...AuthOption) ClientOption {
+	return withAuthMethod(NewTokenAuth(token, opts...))
+}
+
+// WithTokenFile sets the token for the client from the given file, such as a Vault Agent sink.
+// The file is polled for changes and a new token is swapped in as soon as it is written.
+func WithTokenFile(path string, opts ...AuthOption) ClientOption {
+	return withAuthMethod(NewTokenFileAuth(path, opts...))
+}
+
+// WithAppRoleAuth sets the AppRole authentication method for the client.
+func WithAppRoleAuth(roleID, secretID string, opts ...AuthOption) ClientOption {
+	return withAuthMethod(NewAppRoleAuth(roleID, secretID, opts...))
+}
+
+// WithAppRoleAuthFromSource sets the AppRole authentication method for the client, reading the secret ID from the source.
+// Combine it with WithWrappedSecretID when the source returns a response-wrapping token.
+func WithAppRoleAuthFromSource(roleID string, secretID CredentialSource, opts ...AuthOption) ClientOption {
+	return withAuthMethod(NewAppRoleAuthFromSource(roleID, secretID, opts...))
+}
+
+// WithUserPassAuth sets the UserPass authentication method for the client.
+func WithUserPassAuth(username, password string, opts ...AuthOption) ClientOption {
+	return withAuthMethod(NewUserPassAuth(username, password, opts...))
+}
+
+// WithJWTAuth sets the JWT authentication method for the client.
+// The JWT provider is called on every login, so it can return a fresh token each time.
+func WithJWTAuth(role string, jwtProvider JWTProvider, opts ...AuthOption) ClientOption {
+	return withAuthMethod(NewJWTAuth(role, jwtProvider, opts...))
+}
+
+// WithJWTAuthFromFile sets the JWT authentication method for the client using a JWT read from the given file.
+// The file is read again on every login, so rotated tokens are picked up.
+func WithJWTAuthFromFile(role, path string, opts ...AuthOption) ClientOption {
+	return withAuthMethod(NewJWTAuthFromFile(role, path, opts...))
+}
+
+// WithCertAuth sets the TLS certificate authentication method for the client.
+// The key pair is read from the given files and reloaded whenever either file changes.
+func WithCertAuth(certFile, keyFile string, opts ...AuthOption) ClientOption {
+	return withAuthMethod(NewCertAuth(certFile, keyFile, opts...))
+}
+
+// WithCertAuthFromCertificate sets the TLS certificate authentication method for the client using the given certificate.
+func WithCertAuthFromCertificate(cert *tls.Certificate, opts ...AuthOption) ClientOption {
+	return withAuthMethod(NewCertAuthFromCertificate(cert, opts...))
+}
+
+// WithAWSIAMAuth sets the AWS IAM authentication method for the client.
+// The login request is a locally signed sts:GetCallerIdentity request.
+func WithAWSIAMAuth(role string, opts ...AuthOption) ClientOption {
+	return withAuthMethod(NewAWSIAMAuth(role, opts...))
+}
+
+// WithLDAPAuth sets the LDAP authentication method for the client.
+// The password is read from the source on every login.
+func WithLDAPAuth(username string, password CredentialSource, opts ...AuthOption) ClientOption {
+	return withAuthMethod(NewLDAPAuth(username, password, opts...))
+}
+
 // WithKvv2Mount sets the KVv2 mount point for the client.
 func WithKvv2Mount(mount string) ClientOption {
 	return func(c *client) error {
@@ -117,57 +226,23 @@ func WithKvv2Mount(mount string) ClientOption {
 }
 
 // WithKubernetesServiceAccountAuth sets the Kubernetes authentication method for the client using a service account.
-func WithKubernetesServiceAccountAuth(roleName string) ClientOption {
-	return func(c *client) error {
-		if roleName == "" {
-			return errors.New("role name is empty")
-		}
-
-		c.auth = func(v *hashiVault.Client) (*hashiVault.Secret, error) {
-			sec, err := kubernetesLogin(v, roleName, kubernetesAuth.WithServiceAccountTokenPath(kubernetesServiceAccountTokenPath))
-			if err != nil {
-				return nil, err
-			}
-
-			go c.renewAuthInfo()
-
-			return sec, nil
-		}
-		return nil
-	}
+func WithKubernetesServiceAccountAuth(roleName string, opts ...AuthOption) ClientOption {
+	return withAuthMethod(NewKubernetesServiceAccountAuth(roleName, opts...))
 }
 
 // WithKubernetesAuthFromEnv sets the Kubernetes authentication method for the client using a service account from environment variables.
-func WithKubernetesAuthFromEnv() ClientOption {
+func WithKubernetesAuthFromEnv(opts ...AuthOption) ClientOption {
 	return func(c *client) error {
 		roleFromEnv := os.Getenv(envServiceAccountName)
 		if roleFromEnv == "" {
 			return errors.New("role name is not set in environment variable " + envServiceAccountName)
 		}
 
-		return WithKubernetesServiceAccountAuth(roleFromEnv)(c)
+		return WithKubernetesServiceAccountAuth(roleFromEnv, opts...)(c)
 	}
 }
 
 // WithKubernetesAuth sets the Kubernetes authentication method for the client using the role and token provided.
-func WithKubernetesAuth(role, token string) ClientOption {
-	return func(c *client) error {
-		if role == "" {
-			return errors.New("role name is empty")
-		} else if token == "" {
-			return errors.New("token is empty")
-		}
-
-		c.auth = func(v *hashiVault.Client) (*hashiVault.Secret, error) {
-			sec, err := kubernetesLogin(v, role, kubernetesAuth.WithServiceAccountToken(token))
-			if err != nil {
-				return nil, err
-			}
-
-			go c.renewAuthInfo()
-
-			return sec, nil
-		}
-		return nil
-	}
+func WithKubernetesAuth(role, token string, opts ...AuthOption) ClientOption {
+	return withAuthMethod(NewKubernetesAuth(role, token, opts...))
 }
@@ -0,0 +1,419 @@
+package vaulty
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	hashiVault "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient creates a client that does not talk to a real Vault server.
+func newTestClient(t *testing.T, auth loginFunc) *client {
+	t.Helper()
+
+	vc, err := hashiVault.NewClient(hashiVault.DefaultConfig())
+	require.NoError(t, err)
+	vc.SetMaxRetries(0)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+
+	return &client{
+		ctx: ctx,
+		l:   slog.New(slog.DiscardHandler),
+		auth: &builtinAuth{
+			name:      "test",
+			renewable: true,
+			login:     auth,
+		},
+		retryPolicy: &RetryPolicy{
+			InitialInterval: time.Millisecond,
+			MaxInterval:     5 * time.Millisecond,
+			Multiplier:      2,
+		},
+		v:      vc,
+		cancel: cancel,
+		leases: make(map[*trackedLease]struct{}),
+	}
+}
+
+// newTestVaultServer starts a stand-in Vault server serving the given handler.
+func newTestVaultServer(t *testing.T, handler http.Handler) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// writeTestJSON writes v as the JSON response body.
+func writeTestJSON(t *testing.T, w http.ResponseWriter, v any) {
+	t.Helper()
+
+	w.Header().Set("Content-Type", "application/json")
+	require.NoError(t, json.NewEncoder(w).Encode(v))
+}
+
+// expiredAuthResponse is a login response whose token the lifetime watcher treats as already expired,
+// as long as the server does not renew it.
+func expiredAuthResponse(token string) map[string]any {
+	return map[string]any{
+		"auth": map[string]any{
+			"client_token":   token,
+			"renewable":      true,
+			"lease_duration": 0,
+		},
+	}
+}
+
+// longLivedAuthResponse is a login response whose token does not need renewing during a test.
+func longLivedAuthResponse(token string) map[string]any {
+	return map[string]any{
+		"auth": map[string]any{
+			"client_token":   token,
+			"renewable":      false,
+			"lease_duration": 3600,
+		},
+	}
+}
+
+// expiredAuthSecret returns an auth secret that the lifetime watcher treats as already expired,
+// as long as the server does not renew it.
+func expiredAuthSecret(token string) *hashiVault.Secret {
+	return &hashiVault.Secret{
+		Auth: &hashiVault.SecretAuth{
+			ClientToken:   token,
+			Renewable:     true,
+			LeaseDuration: 0,
+		},
+	}
+}
+
+// longLivedAuthSecret returns an auth secret whose token does not need renewing during a test.
+func longLivedAuthSecret(token string) *hashiVault.Secret {
+	return &hashiVault.Secret{
+		Auth: &hashiVault.SecretAuth{
+			ClientToken:   token,
+			Renewable:     false,
+			LeaseDuration: 3600,
+		},
+	}
+}
+
+func TestClient_Reauthenticate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries failed logins until one succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		attempts := 0
+		c := newTestClient(t, func(context.Context, *hashiVault.Client) (*hashiVault.Secret, error) {
+			attempts++
+			if attempts < 3 {
+				return nil, errors.New("vault is unavailable")
+			}
+			return expiredAuthSecret("new-token"), nil
+		})
+
+		var hookErrs []error
+		c.onAuthError = func(err error) {
+			hookErrs = append(hookErrs, err)
+		}
+
+		sec, err := c.reauthenticate()
+		require.NoError(t, err)
+		require.Equal(t, "new-token", sec.Auth.ClientToken)
+		require.Equal(t, sec, c.authInfo())
+		require.Equal(t, "new-token", c.v.Token())
+		require.Equal(t, 3, attempts)
+		require.Len(t, hookErrs, 2)
+		require.ErrorContains(t, hookErrs[0], "vault is unavailable")
+	})
+
+	t.Run("gives up when the retry policy is exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		attempts := 0
+		c := newTestClient(t, func(context.Context, *hashiVault.Client) (*hashiVault.Secret, error) {
+			attempts++
+			return nil, errors.New("permission denied")
+		})
+		c.retryPolicy.MaxAttempts = 4
+
+		sec, err := c.reauthenticate()
+		require.ErrorIs(t, err, ErrAuthRetriesExhausted)
+		require.ErrorContains(t, err, "permission denied")
+		require.Nil(t, sec)
+		require.Equal(t, 4, attempts)
+	})
+
+	t.Run("stops retrying when the context is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		c := newTestClient(t, func(context.Context, *hashiVault.Client) (*hashiVault.Secret, error) {
+			cancel()
+			return nil, errors.New("vault is unavailable")
+		})
+		c.ctx = ctx
+		c.retryPolicy.InitialInterval = time.Hour
+
+		_, err := c.reauthenticate()
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestClient_RenewAuthInfo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports the terminal error instead of exiting", func(t *testing.T) {
+		t.Parallel()
+
+		c := newTestClient(t, func(context.Context, *hashiVault.Client) (*hashiVault.Secret, error) {
+			return nil, errors.New("vault is unavailable")
+		})
+		c.retryPolicy.MaxAttempts = 2
+		c.authCreds = expiredAuthSecret("token")
+
+		errCh := make(chan error, 1)
+		c.authErrCh = errCh
+
+		hookCalls := 0
+		c.onAuthError = func(error) {
+			hookCalls++
+		}
+
+		c.renewAuthInfo()
+
+		select {
+		case err := <-errCh:
+			require.ErrorIs(t, err, ErrAuthRetriesExhausted)
+		default:
+			t.Fatal("expected a terminal auth error")
+		}
+		require.Equal(t, 2, hookCalls)
+	})
+
+	t.Run("does not report an error when the context is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		c := newTestClient(t, func(context.Context, *hashiVault.Client) (*hashiVault.Secret, error) {
+			cancel()
+			return nil, errors.New("vault is unavailable")
+		})
+		c.ctx = ctx
+		c.authCreds = expiredAuthSecret("token")
+
+		errCh := make(chan error, 1)
+		c.authErrCh = errCh
+
+		c.renewAuthInfo()
+
+		require.Empty(t, errCh)
+	})
+}
+
+func TestNewClient_SingleRenewalLoop(t *testing.T) {
+	var logins atomic.Int64
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/approle/login", func(w http.ResponseWriter, _ *http.Request) {
+		logins.Add(1)
+		writeTestJSON(t, w, expiredAuthResponse("token"))
+	})
+	srv := newTestVaultServer(t, mux)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	_, err := NewClient(
+		WithContext(ctx),
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithAppRoleAuth("role-id", "secret-id"),
+	)
+	require.NoError(t, err)
+
+	waitForLogins := func(n int64) {
+		require.Eventually(t, func() bool {
+			return logins.Load() >= n
+		}, 10*time.Second, time.Millisecond)
+	}
+
+	// Every login returns an expired token, so the client keeps logging in again.
+	waitForLogins(10)
+	before := runtime.NumGoroutine()
+
+	waitForLogins(logins.Load() + 200)
+	after := runtime.NumGoroutine()
+
+	require.LessOrEqual(t, after, before+5, "goroutines accumulated across re-logins")
+}
+
+func TestClient_ConcurrentReadsDuringRelogin(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logins      atomic.Int64
+		reads       atomic.Int64
+		emptyTokens atomic.Int64
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/approle/login", func(w http.ResponseWriter, _ *http.Request) {
+		n := logins.Add(1)
+		writeTestJSON(t, w, expiredAuthResponse("token-"+strconv.FormatInt(n, 10)))
+	})
+	mux.HandleFunc("GET /v1/secret/app", func(w http.ResponseWriter, r *http.Request) {
+		reads.Add(1)
+		if r.Header.Get("X-Vault-Token") == "" {
+			emptyTokens.Add(1)
+		}
+		writeTestJSON(t, w, map[string]any{
+			"data": map[string]any{
+				"password": "hunter2",
+			},
+		})
+	})
+	srv := newTestVaultServer(t, mux)
+
+	vc, err := NewClient(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithAppRoleAuth("role-id", "secret-id"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				sec, err := vc.Path("app", WithPrefix("secret")).GetSecret(t.Context())
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.Equal(t, "hunter2", sec.Data["password"])
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int64(400), reads.Load())
+	require.Zero(t, emptyTokens.Load())
+	require.Greater(t, logins.Load(), int64(1), "expected re-logins while reading")
+}
+
+func TestWithNamespace(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		opts               []ClientOption
+		wantLoginNamespace string
+		wantReadNamespace  string
+	}{
+		{
+			name:               "client namespace",
+			opts:               []ClientOption{WithNamespace("org/team-a")},
+			wantLoginNamespace: "org/team-a",
+			wantReadNamespace:  "org/team-a",
+		},
+		{
+			name:               "login namespace",
+			opts:               []ClientOption{WithNamespace("org/team-a"), WithLoginNamespace("org")},
+			wantLoginNamespace: "org",
+			wantReadNamespace:  "org/team-a",
+		},
+		{
+			name:               "root login namespace",
+			opts:               []ClientOption{WithNamespace("org/team-a"), WithLoginNamespace("")},
+			wantLoginNamespace: "",
+			wantReadNamespace:  "org/team-a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var loginNamespace, readNamespace string
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /v1/auth/userpass/login/user", func(w http.ResponseWriter, r *http.Request) {
+				loginNamespace = r.Header.Get("X-Vault-Namespace")
+				writeTestJSON(t, w, longLivedAuthResponse("userpass-token"))
+			})
+			mux.HandleFunc("GET /v1/secret/app", func(w http.ResponseWriter, r *http.Request) {
+				readNamespace = r.Header.Get("X-Vault-Namespace")
+				writeTestJSON(t, w, map[string]any{"data": map[string]any{"key": "value"}})
+			})
+			srv := newTestVaultServer(t, mux)
+
+			vc, err := NewClient(append([]ClientOption{
+				WithLogger(slog.New(slog.DiscardHandler)),
+				WithAddr(srv.URL),
+				WithUserPassAuth("user", "pass"),
+			}, tt.opts...)...)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				require.NoError(t, vc.Close(context.Background()))
+			})
+
+			_, err = vc.Path("secret/app").GetSecret(t.Context())
+			require.NoError(t, err)
+
+			require.Equal(t, tt.wantLoginNamespace, loginNamespace)
+			require.Equal(t, tt.wantReadNamespace, readNamespace)
+		})
+	}
+}
+
+func TestWithNamespace_OverridesEnv(t *testing.T) {
+	t.Setenv(hashiVault.EnvVaultNamespace, "team-a")
+
+	var loginNamespace, readNamespace string
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/userpass/login/user", func(w http.ResponseWriter, r *http.Request) {
+		loginNamespace = r.Header.Get("X-Vault-Namespace")
+		writeTestJSON(t, w, longLivedAuthResponse("userpass-token"))
+	})
+	mux.HandleFunc("GET /v1/secret/app", func(w http.ResponseWriter, r *http.Request) {
+		readNamespace = r.Header.Get("X-Vault-Namespace")
+		writeTestJSON(t, w, map[string]any{"data": map[string]any{"key": "value"}})
+	})
+	srv := newTestVaultServer(t, mux)
+
+	vc, err := NewClient(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithUserPassAuth("user", "pass"),
+		WithNamespace(""),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	_, err = vc.Path("secret/app").GetSecret(t.Context())
+	require.NoError(t, err)
+	require.Empty(t, loginNamespace)
+	require.Empty(t, readNamespace)
+}
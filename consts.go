@@ -1,5 +1,7 @@
 package vaulty
 
+import "time"
+
 const (
 	maxInt = int(^uint(0) >> 1) // maximum value for int
 
@@ -8,6 +10,9 @@ const (
 	loggingKeyResult        = "result"
 	loggingKeyRenewedAt     = "renewed_at"
 	loggingKeyLeaseDuration = "lease_duration"
+	loggingKeyAttempt       = "attempt"
+	loggingKeyLeaseID       = "lease_id"
+	loggingKeyAuthMethod    = "auth_method"
 
 	pathKeyTransitDecrypt = "decrypt"
 	pathKeyTransitEncrypt = "encrypt"
@@ -15,8 +20,79 @@ const (
 	TransitKeyCipherText = "ciphertext"
 	TransitKeyPlainText  = "plaintext"
 
+	authNameToken      = "token"
+	authNameTokenFile  = "token_file"
+	authNameAppRole    = "approle"
+	authNameUserPass   = "userpass"
+	authNameKubernetes = "kubernetes"
+	authNameJWT        = "jwt"
+	authNameCert       = "cert"
+	authNameAWS        = "aws"
+	authNameLDAP       = "ldap"
+	authNameChain      = "chain"
+
+	defaultMountAppRole    = "approle"
+	defaultMountUserPass   = "userpass"
+	defaultMountKubernetes = "kubernetes"
+	defaultMountJWT        = "jwt"
+	defaultMountCert       = "cert"
+	defaultMountAWS        = "aws"
+	defaultMountLDAP       = "ldap"
+	defaultMountTransit    = "transit"
+
+	defaultLoginTimeout = 5 * time.Second
+	defaultPollInterval = 5 * time.Second
+
+	defaultViperSourcePollInterval = 30 * time.Second
+	defaultViperSourceConcurrency  = 8
+
+	defaultWalkConcurrency = 8
+	defaultLoadConcurrency = 8
+
+	mountTypeKv      = "kv"
+	mountTypeGeneric = "generic"
+
+	kvVersion1 = 1
+	kvVersion2 = 2
+
+	tagVault           = "vault"
+	tagOptionRequired  = "required"
+	tagOptionOmitEmpty = "omitempty"
+
+	referenceSchemeKv             = "kv"
+	referenceSchemeTransitDecrypt = "transit-decrypt"
+	referenceSchemeVault          = "vault://"
+	referenceSchemeVaultTransit   = "vault+transit://"
+	referenceParamVersion         = "version"
+
+	defaultAWSRegion         = "us-east-1"
+	awsSTSGlobalEndpoint     = "https://sts.amazonaws.com/"
+	awsGetCallerIdentityBody = "Action=GetCallerIdentity&Version=2011-06-15"
+	awsServerIDHeader        = "X-Vault-AWS-IAM-Server-ID"
+
+	envAWSAccessKeyID           = "AWS_ACCESS_KEY_ID"
+	envAWSSecretAccessKey       = "AWS_SECRET_ACCESS_KEY" // nolint:gosec // This is detected as a secret
+	envAWSSessionToken          = "AWS_SESSION_TOKEN"     // nolint:gosec // This is detected as a secret
+	envAWSSharedCredentialsFile = "AWS_SHARED_CREDENTIALS_FILE"
+	envAWSProfile               = "AWS_PROFILE"
+
 	envServiceAccountName = "SERVICE_ACCOUNT_NAME" // nolint:gosec // This is detected as a secret
 
+	envAuthMethod             = "VAULTY_AUTH_METHOD"
+	envAuthMount              = "VAULTY_AUTH_MOUNT"
+	envAuthNamespace          = "VAULTY_AUTH_NAMESPACE"
+	envAuthRole               = "VAULTY_AUTH_ROLE"
+	envTokenFile              = "VAULTY_TOKEN_FILE" // nolint:gosec // This is detected as a secret
+	envAppRoleRoleID          = "VAULTY_APPROLE_ROLE_ID"
+	envAppRoleSecretID        = "VAULTY_APPROLE_SECRET_ID"         // nolint:gosec // This is detected as a secret
+	envAppRoleSecretIDFile    = "VAULTY_APPROLE_SECRET_ID_FILE"    // nolint:gosec // This is detected as a secret
+	envAppRoleSecretIDWrapped = "VAULTY_APPROLE_SECRET_ID_WRAPPED" // nolint:gosec // This is detected as a secret
+	envUsername               = "VAULTY_USERNAME"
+	envPassword               = "VAULTY_PASSWORD" // nolint:gosec // This is detected as a secret
+	envJWTFile                = "VAULTY_JWT_FILE"
+	envAWSRegion              = "VAULTY_AWS_REGION"
+	envKvv2Mount              = "VAULTY_KVV2_MOUNT"
+
 	// KubernetesServiceAccountTokenPath is the path to the Kubernetes service account token.
 	kubernetesServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token" // nolint:gosec // This is detected as a secret
 )
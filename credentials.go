@@ -0,0 +1,51 @@
+package vaulty
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CredentialSource is a function that returns a credential, such as a password or a secret ID.
+// It is called on every login, so credentials read from files or the environment can change over time.
+type CredentialSource func() (string, error)
+
+// CredentialFromString returns a CredentialSource for the given value.
+func CredentialFromString(value string) CredentialSource {
+	return func() (string, error) {
+		if value == "" {
+			return "", errors.New("credential is empty")
+		}
+		return value, nil
+	}
+}
+
+// CredentialFromFile returns a CredentialSource that reads the credential from the given file.
+// Surrounding whitespace, such as a trailing newline, is trimmed.
+func CredentialFromFile(path string) CredentialSource {
+	return func() (string, error) {
+		b, err := os.ReadFile(path) // nolint:gosec // The path is provided by the application
+		if err != nil {
+			return "", fmt.Errorf("unable to read credential file: %w", err)
+		}
+
+		value := strings.TrimSpace(string(b))
+		if value == "" {
+			return "", fmt.Errorf("credential file %s is empty", path)
+		}
+
+		return value, nil
+	}
+}
+
+// CredentialFromEnv returns a CredentialSource that reads the credential from the given environment variable.
+func CredentialFromEnv(name string) CredentialSource {
+	return func() (string, error) {
+		value := os.Getenv(name)
+		if value == "" {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return value, nil
+	}
+}
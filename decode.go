@@ -0,0 +1,475 @@
+package vaulty
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	hashiVault "github.com/hashicorp/vault/api"
+)
+
+var (
+	// ErrMissingKey is returned when a key of a field tagged as required is not in the secret.
+	ErrMissingKey = errors.New("required key is missing")
+
+	// ErrInvalidValue is returned when the value of a key cannot be converted to the type of its field.
+	ErrInvalidValue = errors.New("invalid value")
+
+	durationType = reflect.TypeFor[time.Duration]()
+	bytesType    = reflect.TypeFor[[]byte]()
+)
+
+// KeyError is returned for every key of a secret that cannot be decoded or encoded.
+type KeyError struct {
+	// Key is the path of the key, such as "database.port" for a nested struct or "hosts[1]" for a slice.
+	Key string
+
+	// Err describes what is wrong with the key. It wraps ErrMissingKey or ErrInvalidValue.
+	Err error
+}
+
+// Error returns the key and what is wrong with it.
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("key %q: %s", e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// Decode decodes the data of a secret into a new value of the struct type T. See DecodeInto.
+func Decode[T any](data map[string]any) (T, error) {
+	var v T
+	err := DecodeInto(data, &v)
+	return v, err
+}
+
+// DecodeInto decodes the data of a secret into the struct v points to.
+//
+// Fields are matched to keys by their vault tag, such as `vault:"password,required"`, or by their name when untagged.
+// Fields tagged with `vault:"-"` and unexported fields are skipped, and untagged embedded structs are flattened.
+// A missing key leaves its field untouched, unless the field is tagged as required.
+//
+// Values are converted to the type of their field: strings are parsed into numbers and booleans, durations are
+// parsed from strings such as "30s" or taken as seconds when numeric, []byte fields are decoded from base64,
+// and nested maps are decoded into structs and maps.
+//
+// Every key that cannot be decoded is reported, as a KeyError in the returned joined error.
+func DecodeInto(data map[string]any, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("unable to decode into %T: must be a non-nil pointer to a struct", v)
+	}
+
+	d := new(decoder)
+	d.decodeStruct("", data, rv.Elem())
+	return errors.Join(d.errs...)
+}
+
+// Encode encodes the struct v, or the struct it points to, into secret data that DecodeInto reads back.
+//
+// Durations are written as strings such as "30s", and []byte fields as base64. Fields tagged with omitempty,
+// such as `vault:"token,omitempty"`, and nil pointers are left out when empty.
+func Encode(v any) (map[string]any, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unable to encode %T: must be a struct or a pointer to a struct", v)
+	}
+
+	e := new(encoder)
+	data := e.encodeStruct("", rv)
+	if err := errors.Join(e.errs...); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// GetInto reads the KV v2 secret and decodes its data into the struct v points to. See DecodeInto.
+func (c *SecretPath) GetInto(ctx context.Context, v any) error {
+	secret, err := c.GetKvSecretV2(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := DecodeInto(secret.Data, v); err != nil {
+		return fmt.Errorf("unable to decode secret: %w", err)
+	}
+	return nil
+}
+
+// PutFrom encodes the struct v and writes it as a new version of the KV v2 secret. See Encode.
+func (c *SecretPath) PutFrom(ctx context.Context, v any) (*hashiVault.KVVersionMetadata, error) {
+	data, err := Encode(v)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode secret: %w", err)
+	}
+
+	return c.PutKvSecretV2(ctx, data)
+}
+
+// vaultTag is a parsed vault struct tag.
+type vaultTag struct {
+	key       string
+	required  bool
+	omitEmpty bool
+}
+
+// parseVaultTag returns the tag of the field, or false if the field is skipped.
+func parseVaultTag(f *reflect.StructField) (vaultTag, bool) {
+	raw, ok := f.Tag.Lookup(tagVault)
+	if raw == "-" || !f.IsExported() {
+		return vaultTag{}, false
+	}
+
+	name, opts, _ := strings.Cut(raw, ",")
+	tag := vaultTag{key: name}
+	if tag.key == "" || !ok {
+		tag.key = f.Name
+	}
+
+	for opt := range strings.SplitSeq(opts, ",") {
+		switch opt {
+		case tagOptionRequired:
+			tag.required = true
+		case tagOptionOmitEmpty:
+			tag.omitEmpty = true
+		}
+	}
+
+	return tag, true
+}
+
+// isFlattened reports whether the field is an untagged embedded struct, whose fields are read from the parent.
+func isFlattened(f *reflect.StructField) bool {
+	_, tagged := f.Tag.Lookup(tagVault)
+	return f.Anonymous && !tagged && f.Type.Kind() == reflect.Struct
+}
+
+// keyPath joins the path of a parent key and a child key.
+func keyPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// decoder decodes secret data, collecting an error for every key that cannot be decoded.
+type decoder struct {
+	errs []error
+}
+
+// fail records an error for the given key.
+func (d *decoder) fail(path string, err error) {
+	d.errs = append(d.errs, &KeyError{Key: path, Err: err})
+}
+
+// invalid records that the value of the given key cannot be converted to the given type.
+func (d *decoder) invalid(path string, raw any, t reflect.Type) {
+	d.fail(path, fmt.Errorf("%w: cannot convert %T to %s", ErrInvalidValue, raw, t))
+}
+
+// decodeStruct decodes the data into the fields of the struct.
+func (d *decoder) decodeStruct(path string, data map[string]any, v reflect.Value) {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if isFlattened(&f) {
+			d.decodeStruct(path, data, v.Field(i))
+			continue
+		}
+
+		tag, ok := parseVaultTag(&f)
+		if !ok {
+			continue
+		}
+
+		fieldPath := keyPath(path, tag.key)
+		raw, ok := data[tag.key]
+		if !ok || raw == nil {
+			if tag.required {
+				d.fail(fieldPath, ErrMissingKey)
+			}
+			continue
+		}
+
+		d.decode(fieldPath, raw, v.Field(i))
+	}
+}
+
+// decode converts the raw value to the type of v and sets it.
+func (d *decoder) decode(path string, raw any, v reflect.Value) {
+	switch v.Type() {
+	case durationType:
+		d.decodeDuration(path, raw, v)
+		return
+	case bytesType:
+		d.decodeBytes(path, raw, v)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		before := len(d.errs)
+		d.decode(path, raw, elem.Elem())
+		if len(d.errs) == before {
+			v.Set(elem)
+		}
+	case reflect.Interface:
+		rv := reflect.ValueOf(raw)
+		if !rv.Type().AssignableTo(v.Type()) {
+			d.invalid(path, raw, v.Type())
+			return
+		}
+		v.Set(rv)
+	case reflect.String:
+		s, ok := scalarString(raw)
+		if !ok {
+			d.invalid(path, raw, v.Type())
+			return
+		}
+		v.SetString(s)
+	case reflect.Bool:
+		d.decodeParsed(path, raw, v, func(s string) error {
+			b, err := strconv.ParseBool(s)
+			v.SetBool(b)
+			return err
+		})
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		d.decodeParsed(path, raw, v, func(s string) error {
+			n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+			v.SetInt(n)
+			return err
+		})
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		d.decodeParsed(path, raw, v, func(s string) error {
+			n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+			v.SetUint(n)
+			return err
+		})
+	case reflect.Float32, reflect.Float64:
+		d.decodeParsed(path, raw, v, func(s string) error {
+			n, err := strconv.ParseFloat(s, v.Type().Bits())
+			v.SetFloat(n)
+			return err
+		})
+	case reflect.Struct:
+		data, ok := raw.(map[string]any)
+		if !ok {
+			d.invalid(path, raw, v.Type())
+			return
+		}
+		d.decodeStruct(path, data, v)
+	case reflect.Map:
+		d.decodeMap(path, raw, v)
+	case reflect.Slice:
+		d.decodeSlice(path, raw, v)
+	default:
+		d.fail(path, fmt.Errorf("%w: unsupported field type %s", ErrInvalidValue, v.Type()))
+	}
+}
+
+// decodeParsed parses the raw value as a string with the given function, which sets v.
+func (d *decoder) decodeParsed(path string, raw any, v reflect.Value, parse func(s string) error) {
+	s, ok := scalarString(raw)
+	if !ok {
+		d.invalid(path, raw, v.Type())
+		return
+	}
+
+	if err := parse(s); err != nil {
+		v.SetZero()
+		d.fail(path, fmt.Errorf("%w: cannot convert %q to %s", ErrInvalidValue, s, v.Type()))
+	}
+}
+
+// decodeDuration sets v to the duration of the raw value, given as a string such as "30s" or as seconds.
+func (d *decoder) decodeDuration(path string, raw any, v reflect.Value) {
+	s, ok := scalarString(raw)
+	if !ok {
+		d.invalid(path, raw, v.Type())
+		return
+	}
+
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		v.SetInt(int64(time.Duration(seconds) * time.Second))
+		return
+	}
+
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		d.fail(path, fmt.Errorf("%w: cannot convert %q to %s", ErrInvalidValue, s, v.Type()))
+		return
+	}
+	v.SetInt(int64(duration))
+}
+
+// decodeBytes sets v to the base64 decoded raw value.
+func (d *decoder) decodeBytes(path string, raw any, v reflect.Value) {
+	switch r := raw.(type) {
+	case []byte:
+		v.SetBytes(r)
+	case string:
+		b, err := base64.StdEncoding.DecodeString(r)
+		if err != nil {
+			d.fail(path, fmt.Errorf("%w: cannot decode base64: %w", ErrInvalidValue, err))
+			return
+		}
+		v.SetBytes(b)
+	default:
+		d.invalid(path, raw, v.Type())
+	}
+}
+
+// decodeMap decodes the raw map into the map v, which must have string keys.
+func (d *decoder) decodeMap(path string, raw any, v reflect.Value) {
+	data, ok := raw.(map[string]any)
+	if !ok || v.Type().Key().Kind() != reflect.String {
+		d.invalid(path, raw, v.Type())
+		return
+	}
+
+	m := reflect.MakeMapWithSize(v.Type(), len(data))
+	for key, value := range data {
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if value != nil {
+			d.decode(keyPath(path, key), value, elem)
+		}
+		m.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), elem)
+	}
+	v.Set(m)
+}
+
+// decodeSlice decodes the raw slice into the slice v.
+func (d *decoder) decodeSlice(path string, raw any, v reflect.Value) {
+	rv := reflect.ValueOf(raw)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		d.invalid(path, raw, v.Type())
+		return
+	}
+
+	s := reflect.MakeSlice(v.Type(), rv.Len(), rv.Len())
+	for i := range rv.Len() {
+		if elem := rv.Index(i).Interface(); elem != nil {
+			d.decode(fmt.Sprintf("%s[%d]", path, i), elem, s.Index(i))
+		}
+	}
+	v.Set(s)
+}
+
+// scalarString returns the raw value as a string, if it is a string, a number or a boolean.
+func scalarString(raw any) (string, bool) {
+	switch r := raw.(type) {
+	case string:
+		return r, true
+	case json.Number:
+		return r.String(), true
+	case bool:
+		return strconv.FormatBool(r), true
+	case float32:
+		return strconv.FormatFloat(float64(r), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(r, 'f', -1, 64), true
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(r), true
+	default:
+		return "", false
+	}
+}
+
+// encoder encodes structs into secret data, collecting an error for every field that cannot be encoded.
+type encoder struct {
+	errs []error
+}
+
+// encodeStruct encodes the fields of the struct into a map.
+func (e *encoder) encodeStruct(path string, v reflect.Value) map[string]any {
+	data := make(map[string]any)
+	e.encodeFields(path, v, data)
+	return data
+}
+
+// encodeFields encodes the fields of the struct into the given map.
+func (e *encoder) encodeFields(path string, v reflect.Value, data map[string]any) {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if isFlattened(&f) {
+			e.encodeFields(path, v.Field(i), data)
+			continue
+		}
+
+		tag, ok := parseVaultTag(&f)
+		if !ok {
+			continue
+		}
+
+		fv := v.Field(i)
+		if (tag.omitEmpty && fv.IsZero()) || (fv.Kind() == reflect.Pointer && fv.IsNil()) {
+			continue
+		}
+
+		data[tag.key] = e.encode(keyPath(path, tag.key), fv)
+	}
+}
+
+// encode returns the value of v as it is stored in a secret.
+func (e *encoder) encode(path string, v reflect.Value) any {
+	switch v.Type() {
+	case durationType:
+		return time.Duration(v.Int()).String()
+	case bytesType:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return e.encode(path, v.Elem())
+	case reflect.String:
+		return v.String()
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return v.Interface()
+	case reflect.Struct:
+		return e.encodeStruct(path, v)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			e.errs = append(e.errs, &KeyError{Key: path, Err: fmt.Errorf("%w: map keys must be strings", ErrInvalidValue)})
+			return nil
+		}
+
+		m := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			m[key] = e.encode(keyPath(path, key), iter.Value())
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		s := make([]any, v.Len())
+		for i := range v.Len() {
+			s[i] = e.encode(fmt.Sprintf("%s[%d]", path, i), v.Index(i))
+		}
+		return s
+	default:
+		e.errs = append(e.errs, &KeyError{
+			Key: path,
+			Err: fmt.Errorf("%w: unsupported field type %s", ErrInvalidValue, v.Type()),
+		})
+		return nil
+	}
+}
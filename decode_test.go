@@ -0,0 +1,209 @@
+package vaulty
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testDatabaseSecret struct {
+	Host     string `vault:"host,required"`
+	Port     int    `vault:"port"`
+	Username string `vault:"username,required"`
+	Password string `vault:"password,required"`
+}
+
+type testAppSecret struct {
+	testDatabaseSecret
+
+	Debug    bool              `vault:"debug"`
+	Timeout  time.Duration     `vault:"timeout"`
+	TTL      time.Duration     `vault:"ttl"`
+	Key      []byte            `vault:"key"`
+	Ratio    float64           `vault:"ratio"`
+	Retries  *uint8            `vault:"retries"`
+	Hosts    []string          `vault:"hosts"`
+	Labels   map[string]string `vault:"labels"`
+	Cache    testCacheSecret   `vault:"cache"`
+	Extra    any               `vault:"extra"`
+	Token    string            `vault:"token,omitempty"`
+	Region   string
+	Internal string `vault:"-"`
+}
+
+type testCacheSecret struct {
+	Addr string `vault:"addr,required"`
+	DB   int    `vault:"db"`
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	retries := uint8(3)
+	got, err := Decode[testAppSecret](map[string]any{
+		"host":     "db.internal",
+		"port":     json.Number("5432"),
+		"username": "app",
+		"password": "secret",
+		"debug":    "true",
+		"timeout":  "1m30s",
+		"ttl":      json.Number("3600"),
+		"key":      "aGVsbG8=",
+		"ratio":    "0.5",
+		"retries":  "3",
+		"hosts":    []any{"a", "b"},
+		"labels":   map[string]any{"team": "platform", "tier": json.Number("1")},
+		"cache":    map[string]any{"addr": "redis:6379", "db": 2},
+		"extra":    map[string]any{"nested": true},
+		"Region":   "eu-west-1",
+		"Internal": "ignored",
+	})
+	require.NoError(t, err)
+	require.Equal(t, testAppSecret{
+		testDatabaseSecret: testDatabaseSecret{
+			Host:     "db.internal",
+			Port:     5432,
+			Username: "app",
+			Password: "secret",
+		},
+		Debug:   true,
+		Timeout: 90 * time.Second,
+		TTL:     time.Hour,
+		Key:     []byte("hello"),
+		Ratio:   0.5,
+		Retries: &retries,
+		Hosts:   []string{"a", "b"},
+		Labels:  map[string]string{"team": "platform", "tier": "1"},
+		Cache:   testCacheSecret{Addr: "redis:6379", DB: 2},
+		Extra:   map[string]any{"nested": true},
+		Region:  "eu-west-1",
+	}, got)
+}
+
+func TestDecode_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := Decode[testAppSecret](map[string]any{
+		"host":    "db.internal",
+		"port":    "not-a-number",
+		"debug":   "maybe",
+		"timeout": "soon",
+		"key":     "not base64!",
+		"retries": "300",
+		"hosts":   []any{"a", 1, []any{}},
+		"cache":   map[string]any{"db": "two"},
+	})
+	require.ErrorIs(t, err, ErrMissingKey)
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	require.True(t, ok)
+
+	var keys []string
+	for _, e := range joined.Unwrap() {
+		var keyErr *KeyError
+		require.ErrorAs(t, e, &keyErr)
+		keys = append(keys, keyErr.Key)
+	}
+	require.ElementsMatch(t, []string{
+		"port", "username", "password", "debug", "timeout", "key", "retries", "hosts[2]", "cache.addr", "cache.db",
+	}, keys)
+	require.ErrorContains(t, err, `key "port": invalid value: cannot convert "not-a-number" to int`)
+}
+
+func TestDecodeInto_InvalidTarget(t *testing.T) {
+	t.Parallel()
+
+	var secret testDatabaseSecret
+	require.ErrorContains(t, DecodeInto(map[string]any{}, secret), "must be a non-nil pointer to a struct")
+
+	var n int
+	require.ErrorContains(t, DecodeInto(map[string]any{}, &n), "must be a non-nil pointer to a struct")
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	secret := testAppSecret{
+		testDatabaseSecret: testDatabaseSecret{Host: "db.internal", Port: 5432, Username: "app", Password: "secret"},
+		Timeout:            90 * time.Second,
+		Key:                []byte("hello"),
+		Hosts:              []string{"a", "b"},
+		Labels:             map[string]string{"team": "platform"},
+		Cache:              testCacheSecret{Addr: "redis:6379"},
+		Internal:           "ignored",
+	}
+
+	data, err := Encode(&secret)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"host":     "db.internal",
+		"port":     5432,
+		"username": "app",
+		"password": "secret",
+		"debug":    false,
+		"timeout":  "1m30s",
+		"ttl":      "0s",
+		"key":      "aGVsbG8=",
+		"ratio":    float64(0),
+		"hosts":    []any{"a", "b"},
+		"labels":   map[string]any{"team": "platform"},
+		"cache":    map[string]any{"addr": "redis:6379", "db": 0},
+		"extra":    nil,
+		"Region":   "",
+	}, data)
+
+	decoded, err := Decode[testAppSecret](data)
+	require.NoError(t, err)
+	secret.Internal = ""
+	require.Equal(t, secret, decoded)
+
+	_, err = Encode(struct {
+		Ch chan int `vault:"ch"`
+	}{})
+	require.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func TestSecretPath_GetIntoPutFrom(t *testing.T) {
+	t.Parallel()
+
+	var written map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/secret/data/db", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestJSON(t, w, map[string]any{
+			"data": map[string]any{
+				"data":     map[string]any{"host": "db.internal", "port": 5432, "username": "app"},
+				"metadata": kvVersionResponse(1)["data"],
+			},
+		})
+	})
+	mux.HandleFunc("PUT /v1/secret/data/db", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Data map[string]any `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		written = body.Data
+		writeTestJSON(t, w, kvVersionResponse(2))
+	})
+	vc := newTestPathClient(t, mux, WithKvv2Mount("secret"))
+
+	var secret testDatabaseSecret
+	err := vc.Path("db").GetInto(context.Background(), &secret)
+	require.ErrorIs(t, err, ErrMissingKey)
+	require.ErrorContains(t, err, `unable to decode secret: key "password": required key is missing`)
+
+	secret.Password = "secret"
+	metadata, err := vc.Path("db").PutFrom(context.Background(), secret)
+	require.NoError(t, err)
+	require.Equal(t, 2, metadata.Version)
+	require.Equal(t, map[string]any{
+		"host":     "db.internal",
+		"port":     float64(5432),
+		"username": "app",
+		"password": "secret",
+	}, written)
+}
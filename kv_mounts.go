@@ -0,0 +1,120 @@
+package vaulty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	hashiVault "github.com/hashicorp/vault/api"
+)
+
+// ErrNotKvMount is returned when the mount of a path is not a KV secrets engine.
+var ErrNotKvMount = errors.New("mount is not a KV secrets engine")
+
+// kvMountClient is implemented by clients that cache the KV version of their mounts.
+type kvMountClient interface {
+	kvMountVersion(ctx context.Context, vc *hashiVault.Client, namespace, mount string) (int, error)
+}
+
+// GetKv retrieves the secret from the specified path, using the KV version of its mount.
+//
+// The version of every mount is read from sys/mounts the first time it is needed and cached by the client,
+// so the token must be allowed to read sys/mounts.
+func (c *SecretPath) GetKv(ctx context.Context) (*hashiVault.KVSecret, error) {
+	version, err := c.kvVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if version == kvVersion1 {
+		return c.GetKvSecretV1(ctx)
+	}
+	return c.GetKvSecretV2(ctx)
+}
+
+// PutKv writes the given data to the specified path, using the KV version of its mount.
+// On a KV v2 mount, the data is written as a new version of the secret.
+func (c *SecretPath) PutKv(ctx context.Context, data map[string]any) error {
+	version, err := c.kvVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if version == kvVersion1 {
+		return c.PutKvSecretV1(ctx, data)
+	}
+
+	_, err = c.PutKvSecretV2(ctx, data)
+	return err
+}
+
+// kvVersion returns the KV version of the mount of the path.
+func (c *SecretPath) kvVersion(ctx context.Context) (int, error) {
+	vc := c.vaultClient()
+
+	if kc, ok := c.client.(kvMountClient); ok {
+		return kc.kvMountVersion(ctx, vc, vc.Namespace(), c.mount)
+	}
+
+	mounts, err := readKvMounts(ctx, vc)
+	if err != nil {
+		return 0, err
+	}
+	return kvMountVersion(mounts, c.mount)
+}
+
+// kvMountVersion returns the KV version of the given mount, reading the mounts of the namespace on first use.
+func (c *client) kvMountVersion(ctx context.Context, vc *hashiVault.Client, namespace, mount string) (int, error) {
+	c.kvMountsMu.Lock()
+	defer c.kvMountsMu.Unlock()
+
+	mounts, ok := c.kvMounts[namespace]
+	if !ok {
+		var err error
+		mounts, err = readKvMounts(ctx, vc)
+		if err != nil {
+			return 0, err
+		}
+		c.kvMounts[namespace] = mounts
+	}
+
+	return kvMountVersion(mounts, mount)
+}
+
+// readKvMounts returns the KV version of every KV mount, keyed by the mount path without slashes.
+func readKvMounts(ctx context.Context, vc *hashiVault.Client) (map[string]int, error) {
+	mounts, err := vc.Sys().ListMountsWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read mounts: %w", err)
+	}
+
+	versions := make(map[string]int)
+	for path, mount := range mounts {
+		if mount == nil {
+			continue
+		}
+
+		switch mount.Type {
+		case mountTypeKv:
+			if mount.Options["version"] == "2" {
+				versions[strings.Trim(path, "/")] = kvVersion2
+			} else {
+				versions[strings.Trim(path, "/")] = kvVersion1
+			}
+		case mountTypeGeneric:
+			versions[strings.Trim(path, "/")] = kvVersion1
+		}
+	}
+
+	return versions, nil
+}
+
+// kvMountVersion returns the KV version of the given mount.
+func kvMountVersion(mounts map[string]int, mount string) (int, error) {
+	version, ok := mounts[strings.Trim(mount, "/")]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrNotKvMount, mount)
+	}
+	return version, nil
+}
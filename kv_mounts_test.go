@@ -0,0 +1,131 @@
+package vaulty
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestMounts writes a sys/mounts response with a KV v1, a KV v2, a generic and a transit mount.
+func writeTestMounts(t *testing.T, w http.ResponseWriter) {
+	t.Helper()
+
+	writeTestJSON(t, w, map[string]any{
+		"data": map[string]any{
+			"legacy/":  map[string]any{"type": "kv", "options": map[string]any{"version": "1"}},
+			"secret/":  map[string]any{"type": "kv", "options": map[string]any{"version": "2"}},
+			"old/":     map[string]any{"type": "generic"},
+			"transit/": map[string]any{"type": "transit"},
+		},
+	})
+}
+
+func TestSecretPath_GetKv(t *testing.T) {
+	t.Parallel()
+
+	var mountReads atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/sys/mounts", func(w http.ResponseWriter, _ *http.Request) {
+		mountReads.Add(1)
+		writeTestMounts(t, w)
+	})
+	mux.HandleFunc("GET /v1/legacy/app", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestJSON(t, w, map[string]any{"data": map[string]any{"engine": "v1"}})
+	})
+	mux.HandleFunc("GET /v1/old/app", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestJSON(t, w, map[string]any{"data": map[string]any{"engine": "generic"}})
+	})
+	mux.HandleFunc("GET /v1/secret/data/app", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestJSON(t, w, map[string]any{
+			"data": map[string]any{
+				"data":     map[string]any{"engine": "v2"},
+				"metadata": kvVersionResponse(3)["data"],
+			},
+		})
+	})
+	vc := newTestPathClient(t, mux)
+
+	tests := []struct {
+		mount string
+		want  string
+	}{
+		{mount: "legacy", want: "v1"},
+		{mount: "old", want: "generic"},
+		{mount: "secret", want: "v2"},
+		{mount: "/secret/", want: "v2"},
+	}
+
+	for _, tt := range tests {
+		secret, err := vc.Path("app", WithMount(tt.mount)).GetKv(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"engine": tt.want}, secret.Data)
+	}
+
+	_, err := vc.Path("app", WithMount("transit")).GetKv(context.Background())
+	require.ErrorIs(t, err, ErrNotKvMount)
+
+	_, err = vc.Path("app", WithMount("missing")).GetKv(context.Background())
+	require.ErrorIs(t, err, ErrNotKvMount)
+
+	require.Equal(t, int32(1), mountReads.Load())
+}
+
+func TestSecretPath_PutKv(t *testing.T) {
+	t.Parallel()
+
+	bodies := make(map[string]map[string]any)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/sys/mounts", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestMounts(t, w)
+	})
+	mux.HandleFunc("PUT /v1/legacy/app", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies["legacy"] = body
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("PUT /v1/secret/data/app", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies["secret"] = body
+		writeTestJSON(t, w, kvVersionResponse(1))
+	})
+	vc := newTestPathClient(t, mux)
+
+	require.NoError(t, vc.Path("app", WithMount("legacy")).PutKv(context.Background(), map[string]any{"key": "v1"}))
+	require.NoError(t, vc.Path("app", WithMount("secret")).PutKv(context.Background(), map[string]any{"key": "v2"}))
+
+	require.Equal(t, map[string]any{"key": "v1"}, bodies["legacy"])
+	require.Equal(t, map[string]any{"data": map[string]any{"key": "v2"}}, bodies["secret"])
+}
+
+func TestSecretPath_GetKv_MountsUnreadable(t *testing.T) {
+	t.Parallel()
+
+	var mountReads atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/sys/mounts", func(w http.ResponseWriter, _ *http.Request) {
+		if mountReads.Add(1) == 1 {
+			writeTestVaultError(t, w, http.StatusForbidden, "permission denied")
+			return
+		}
+		writeTestMounts(t, w)
+	})
+	mux.HandleFunc("GET /v1/legacy/app", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestJSON(t, w, map[string]any{"data": map[string]any{"engine": "v1"}})
+	})
+	vc := newTestPathClient(t, mux)
+
+	_, err := vc.Path("app", WithMount("legacy")).GetKv(context.Background())
+	require.ErrorContains(t, err, "unable to read mounts")
+
+	// A failed read is not cached, so the next call reads the mounts again.
+	secret, err := vc.Path("app", WithMount("legacy")).GetKv(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"engine": "v1"}, secret.Data)
+	require.Equal(t, int32(2), mountReads.Load())
+}
@@ -0,0 +1,40 @@
+package vaulty
+
+import (
+	"context"
+	"fmt"
+
+	hashiVault "github.com/hashicorp/vault/api"
+)
+
+// GetKvSecretV1 retrieves a secret from the specified path of a KV v1 mount.
+func (c *SecretPath) GetKvSecretV1(ctx context.Context) (*hashiVault.KVSecret, error) {
+	secret, err := c.vaultClient().KVv1(c.mount).Get(ctx, c.path())
+	if err != nil {
+		return nil, fmt.Errorf("unable to read secret: %w", err)
+	} else if secret == nil {
+		return nil, ErrSecretNotFound
+	}
+	return secret, nil
+}
+
+// PutKvSecretV1 writes the given data to the specified path of a KV v1 mount, replacing the secret.
+func (c *SecretPath) PutKvSecretV1(ctx context.Context, data map[string]any) error {
+	if err := c.vaultClient().KVv1(c.mount).Put(ctx, c.path(), data); err != nil {
+		return fmt.Errorf("unable to write secret: %w", err)
+	}
+	return nil
+}
+
+// DeleteKvSecretV1 deletes the secret at the specified path of a KV v1 mount.
+func (c *SecretPath) DeleteKvSecretV1(ctx context.Context) error {
+	if err := c.vaultClient().KVv1(c.mount).Delete(ctx, c.path()); err != nil {
+		return fmt.Errorf("unable to delete secret: %w", err)
+	}
+	return nil
+}
+
+// ListKvV1 returns the names of the secrets and folders directly below the KV v1 path. Folder names end with a slash.
+func (c *SecretPath) ListKvV1(ctx context.Context) ([]string, error) {
+	return listKeysAt(ctx, c.vaultClient(), fmt.Sprintf("%s/%s", c.mount, c.path()), c.path())
+}
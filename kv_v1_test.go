@@ -0,0 +1,61 @@
+package vaulty
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretPath_KvV1(t *testing.T) {
+	t.Parallel()
+
+	store := map[string]map[string]any{
+		"app/db": {"password": "initial"},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/legacy/{path...}", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("list") == "true" {
+			writeTestJSON(t, w, map[string]any{"data": map[string]any{"keys": []string{"db", "nested/"}}})
+			return
+		}
+
+		data, ok := store[r.PathValue("path")]
+		if !ok {
+			writeTestVaultError(t, w, http.StatusNotFound, "")
+			return
+		}
+		writeTestJSON(t, w, map[string]any{"data": data})
+	})
+	mux.HandleFunc("PUT /v1/legacy/{path...}", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		store[r.PathValue("path")] = body
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("DELETE /v1/legacy/{path...}", func(w http.ResponseWriter, r *http.Request) {
+		delete(store, r.PathValue("path"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	vc := newTestPathClient(t, mux)
+	path := vc.Path("db", WithMount("legacy"), WithPrefix("app"))
+
+	secret, err := path.GetKvSecretV1(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"password": "initial"}, secret.Data)
+
+	require.NoError(t, path.PutKvSecretV1(context.Background(), map[string]any{"password": "rotated"}))
+	secret, err = path.GetKvSecretV1(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"password": "rotated"}, secret.Data)
+
+	keys, err := vc.Path("app", WithMount("legacy")).ListKvV1(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"db", "nested/"}, keys)
+
+	require.NoError(t, path.DeleteKvSecretV1(context.Background()))
+	_, err = path.GetKvSecretV1(context.Background())
+	require.ErrorIs(t, err, ErrSecretNotFound)
+}
@@ -0,0 +1,166 @@
+package vaulty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	hashiVault "github.com/hashicorp/vault/api"
+)
+
+// ErrClientClosed is returned when the client has been closed.
+var ErrClientClosed = errors.New("client is closed")
+
+// leaseTracker is implemented by clients that track the leases renewed through them.
+type leaseTracker interface {
+	// trackLease registers the lease and returns a context that is cancelled when the client is closed.
+	// The release function must be called once renewal of the lease stops.
+	trackLease(ctx context.Context, secret *hashiVault.Secret) (context.Context, *trackedLease, func(), error)
+}
+
+// trackedLease holds the current lease ID of a secret being renewed.
+type trackedLease struct {
+	mu      sync.Mutex
+	leaseID string
+}
+
+// set updates the lease ID from the given secret.
+func (t *trackedLease) set(secret *hashiVault.Secret) {
+	if t == nil || secret == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.leaseID = secret.LeaseID
+}
+
+// id returns the current lease ID.
+func (t *trackedLease) id() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.leaseID
+}
+
+// goBackground runs fn in a goroutine that Close waits for.
+func (c *client) goBackground(fn func()) error {
+	c.lifecycleMu.Lock()
+	defer c.lifecycleMu.Unlock()
+
+	if c.closed {
+		return ErrClientClosed
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		fn()
+	}()
+
+	return nil
+}
+
+// trackLease registers the lease with the client so that it is stopped and optionally revoked on close.
+func (c *client) trackLease(ctx context.Context, secret *hashiVault.Secret) (context.Context, *trackedLease, func(), error) {
+	c.lifecycleMu.Lock()
+	defer c.lifecycleMu.Unlock()
+
+	if c.closed {
+		return nil, nil, nil, ErrClientClosed
+	}
+
+	lease := new(trackedLease)
+	lease.set(secret)
+	c.leases[lease] = struct{}{}
+	c.wg.Add(1)
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(c.ctx, cancel)
+
+	release := func() {
+		stop()
+		cancel()
+
+		// Leases stopped by Close stay tracked so that they can be revoked.
+		if c.ctx.Err() == nil {
+			c.lifecycleMu.Lock()
+			delete(c.leases, lease)
+			c.lifecycleMu.Unlock()
+		}
+
+		c.wg.Done()
+	}
+
+	return ctx, lease, release, nil
+}
+
+// Close stops the background renewals started by the client and waits for them to finish.
+// If the client was created with WithRevokeOnClose, the tracked leases and the client token are revoked.
+// If the context is done before the renewals finish, Close can be called again to finish closing the client.
+// Calling Close after it has succeeded has no effect.
+func (c *client) Close(ctx context.Context) error {
+	c.lifecycleMu.Lock()
+	if c.shutDown {
+		c.lifecycleMu.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.lifecycleMu.Unlock()
+
+	c.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("unable to wait for background renewals: %w", ctx.Err())
+	}
+
+	// Only the first Close to see the renewals finish revokes the leases and the token.
+	c.lifecycleMu.Lock()
+	shutDown := c.shutDown
+	c.shutDown = true
+	c.lifecycleMu.Unlock()
+
+	if shutDown || !c.revokeOnClose {
+		return nil
+	}
+
+	return c.revoke(ctx)
+}
+
+// revoke revokes the tracked leases and then the client token.
+func (c *client) revoke(ctx context.Context) error {
+	c.lifecycleMu.Lock()
+	leaseIDs := make([]string, 0, len(c.leases))
+	for lease := range c.leases {
+		if id := lease.id(); id != "" {
+			leaseIDs = append(leaseIDs, id)
+		}
+	}
+	c.lifecycleMu.Unlock()
+
+	errs := make([]error, 0)
+	for _, id := range leaseIDs {
+		if err := c.v.Sys().RevokeWithContext(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("unable to revoke lease %s: %w", id, err))
+			continue
+		}
+		c.l.Debug("lease revoked", slog.String(loggingKeyLeaseID, id))
+	}
+
+	if err := c.v.Auth().Token().RevokeSelfWithContext(ctx, ""); err != nil {
+		errs = append(errs, fmt.Errorf("unable to revoke token: %w", err))
+	} else {
+		c.l.Debug("token revoked")
+	}
+
+	return errors.Join(errs...)
+}
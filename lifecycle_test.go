@@ -0,0 +1,185 @@
+package vaulty
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	hashiVault "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Close(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stops the auth renewal loop", func(t *testing.T) {
+		t.Parallel()
+
+		var logins atomic.Int64
+		mux := http.NewServeMux()
+		mux.HandleFunc("PUT /v1/auth/approle/login", func(w http.ResponseWriter, _ *http.Request) {
+			logins.Add(1)
+			writeTestJSON(t, w, expiredAuthResponse("token"))
+		})
+		srv := newTestVaultServer(t, mux)
+
+		vc, err := NewClient(
+			WithLogger(slog.New(slog.DiscardHandler)),
+			WithAddr(srv.URL),
+			WithAppRoleAuth("role-id", "secret-id"),
+		)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			return logins.Load() > 5
+		}, 5*time.Second, time.Millisecond)
+
+		require.NoError(t, vc.Close(t.Context()))
+
+		// A login aborted by Close may still reach the server, so let it settle first.
+		time.Sleep(20 * time.Millisecond)
+		stoppedAt := logins.Load()
+		time.Sleep(50 * time.Millisecond)
+		require.Equal(t, stoppedAt, logins.Load())
+
+		// Closing again is a no-op.
+		require.NoError(t, vc.Close(t.Context()))
+	})
+
+	t.Run("revokes tracked leases and the token", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			mu           sync.Mutex
+			revokedLease string
+			tokenRevoked bool
+		)
+		mux := http.NewServeMux()
+		mux.HandleFunc("PUT /v1/auth/approle/login", func(w http.ResponseWriter, _ *http.Request) {
+			writeTestJSON(t, w, map[string]any{
+				"auth": map[string]any{
+					"client_token":   "token",
+					"renewable":      true,
+					"lease_duration": 3600,
+				},
+			})
+		})
+		mux.HandleFunc("PUT /v1/auth/token/renew-self", func(w http.ResponseWriter, _ *http.Request) {
+			writeTestJSON(t, w, map[string]any{
+				"auth": map[string]any{
+					"client_token":   "token",
+					"renewable":      true,
+					"lease_duration": 3600,
+				},
+			})
+		})
+		mux.HandleFunc("PUT /v1/sys/leases/renew", func(w http.ResponseWriter, _ *http.Request) {
+			writeTestJSON(t, w, map[string]any{
+				"lease_id":       "database/creds/app/abc",
+				"renewable":      true,
+				"lease_duration": 3600,
+			})
+		})
+		mux.HandleFunc("PUT /v1/sys/leases/revoke", func(w http.ResponseWriter, r *http.Request) {
+			body := make(map[string]any)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			mu.Lock()
+			revokedLease, _ = body["lease_id"].(string)
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		})
+		mux.HandleFunc("PUT /v1/auth/token/revoke-self", func(w http.ResponseWriter, _ *http.Request) {
+			mu.Lock()
+			tokenRevoked = true
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		})
+		srv := newTestVaultServer(t, mux)
+
+		l := slog.New(slog.DiscardHandler)
+		vc, err := NewClient(
+			WithLogger(l),
+			WithAddr(srv.URL),
+			WithAppRoleAuth("role-id", "secret-id"),
+			WithRevokeOnClose(),
+		)
+		require.NoError(t, err)
+
+		renewErr := make(chan error, 1)
+		go func() {
+			renewErr <- RenewLease(context.Background(), l, vc, "database", &hashiVault.Secret{
+				LeaseID:       "database/creds/app/abc",
+				Renewable:     true,
+				LeaseDuration: 3600,
+			}, func() (*hashiVault.Secret, error) {
+				return nil, nil
+			})
+		}()
+
+		// Wait for the lease to be registered with the client.
+		require.Eventually(t, func() bool {
+			c, ok := vc.(*client)
+			require.True(t, ok)
+			c.lifecycleMu.Lock()
+			defer c.lifecycleMu.Unlock()
+			return len(c.leases) == 1
+		}, 5*time.Second, time.Millisecond)
+
+		require.NoError(t, vc.Close(t.Context()))
+		require.NoError(t, <-renewErr)
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, "database/creds/app/abc", revokedLease)
+		require.True(t, tokenRevoked)
+	})
+
+	t.Run("revokes the token when close is retried after a timeout", func(t *testing.T) {
+		t.Parallel()
+
+		var revocations atomic.Int64
+		mux := http.NewServeMux()
+		mux.HandleFunc("PUT /v1/auth/token/revoke-self", func(w http.ResponseWriter, _ *http.Request) {
+			revocations.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		})
+		srv := newTestVaultServer(t, mux)
+
+		c := newTestClient(t, nil)
+		c.revokeOnClose = true
+		require.NoError(t, c.v.SetAddress(srv.URL))
+
+		release := make(chan struct{})
+		require.NoError(t, c.goBackground(func() {
+			<-release
+		}))
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		require.ErrorIs(t, c.Close(ctx), context.Canceled)
+		require.Zero(t, revocations.Load())
+
+		close(release)
+		require.NoError(t, c.Close(t.Context()))
+		require.Equal(t, int64(1), revocations.Load())
+
+		// Closing again is a no-op.
+		require.NoError(t, c.Close(t.Context()))
+		require.Equal(t, int64(1), revocations.Load())
+	})
+
+	t.Run("renewing a lease after close fails", func(t *testing.T) {
+		t.Parallel()
+
+		c := newTestClient(t, nil)
+		require.NoError(t, c.Close(t.Context()))
+
+		err := RenewLease(t.Context(), c.l, c, "database", &hashiVault.Secret{}, nil)
+		require.ErrorIs(t, err, ErrClientClosed)
+	})
+}
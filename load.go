@@ -0,0 +1,322 @@
+package vaulty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidReference is returned when a secret reference is malformed.
+var ErrInvalidReference = errors.New("invalid secret reference")
+
+// ReferenceError is returned by Load and the Resolver for every field or config key whose secret reference
+// cannot be resolved.
+type ReferenceError struct {
+	// Field is the path of the struct field, such as "Payments.StripeKey", or the config key.
+	Field string
+
+	// Reference is the secret reference, such as "kv:apps/payments#stripe_key" or "vault://kv/apps/api#token".
+	Reference string
+
+	// Err describes why the reference cannot be resolved.
+	Err error
+}
+
+// Error returns the field, its reference and why it cannot be resolved.
+func (e *ReferenceError) Error() string {
+	return fmt.Sprintf("field %s (%s): %s", e.Field, e.Reference, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ReferenceError) Unwrap() error {
+	return e.Err
+}
+
+// Load fills in the fields of the config struct v points to from the secrets their vault tags reference.
+//
+// A tag of the form `vault:"kv:apps/payments#stripe_key"` sets the field to the stripe_key key of the KV v2 secret
+// apps/payments. Without a key, such as `vault:"kv:apps/payments"`, the whole secret is decoded into the field,
+// which is then usually a struct or a map. A tag of the form `vault:"transit-decrypt:payments#CardCipher"` sets the
+// field to the plaintext of the ciphertext held by the CardCipher field, decrypted with the payments transit key.
+// Without a field, the ciphertext is read from the field itself. Values are converted as DecodeInto does.
+//
+// Nested structs are walked, and other fields, including vault tags without a reference, are left untouched.
+// A pointer to a struct of a type already being walked, such as the parent of a tree node, is not followed.
+// Every secret is read once however many fields reference it, with bounded concurrency. The ciphertexts are decrypted
+// after the KV secrets are read, so a ciphertext can itself come from a kv reference.
+//
+// Every reference that cannot be resolved is reported, as a ReferenceError in the returned joined error.
+func Load(ctx context.Context, client Client, v any, opts ...LoadOption) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("unable to load into %T: must be a non-nil pointer to a struct", v)
+	} else if client == nil {
+		return errors.New("client is nil")
+	}
+
+	l := &loader{
+		client:  client,
+		opts:    newLoadOptions(opts...),
+		walking: make(map[reflect.Type]bool),
+	}
+	l.collect("", rv.Elem())
+
+	l.loadKv(ctx)
+	l.loadTransit(ctx)
+
+	return errors.Join(l.errs...)
+}
+
+// loadField is a struct field with a secret reference.
+type loadField struct {
+	name      string
+	reference string
+	scheme    string
+	path      string
+	key       string
+	v         reflect.Value
+	invalid   bool
+
+	// source is the field holding the ciphertext of a transit-decrypt reference.
+	source reflect.Value
+}
+
+// transitInput is a ciphertext to decrypt with a transit key.
+type transitInput struct {
+	key        string
+	ciphertext string
+}
+
+// transitResult is the plaintext of a ciphertext, or why it could not be decrypted.
+type transitResult struct {
+	plaintext string
+	err       error
+}
+
+// kvResult is the data of a KV secret, or why it could not be read.
+type kvResult struct {
+	data map[string]any
+	err  error
+}
+
+// loader holds the state of one Load.
+type loader struct {
+	client Client
+	opts   *loadOptions
+	fields []*loadField
+	errs   []error
+
+	// walking holds the struct types between the config struct and the struct being collected.
+	walking map[reflect.Type]bool
+}
+
+// fail records an error for the given field.
+func (l *loader) fail(f *loadField, err error) {
+	l.errs = append(l.errs, &ReferenceError{Field: f.name, Reference: f.reference, Err: err})
+}
+
+// reject records that the reference of the given field is malformed.
+func (l *loader) reject(f *loadField, format string, args ...any) {
+	f.invalid = true
+	l.fail(f, fmt.Errorf("%w: "+format, append([]any{ErrInvalidReference}, args...)...))
+}
+
+// collect finds the fields with a secret reference in the struct and its nested structs.
+func (l *loader) collect(prefix string, v reflect.Value) {
+	t := v.Type()
+	l.walking[t] = true
+	defer delete(l.walking, t)
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name := keyPath(prefix, sf.Name)
+		tag := sf.Tag.Get(tagVault)
+		scheme, rest, ok := strings.Cut(tag, ":")
+		if !ok || (scheme != referenceSchemeKv && scheme != referenceSchemeTransitDecrypt) {
+			if sf.Anonymous {
+				// The fields of embedded structs are named as if they were fields of the parent.
+				name = prefix
+			}
+			l.collectNested(name, v.Field(i))
+			continue
+		}
+
+		f := &loadField{
+			name:      name,
+			reference: tag,
+			scheme:    scheme,
+			v:         v.Field(i),
+		}
+		f.path, f.key, _ = strings.Cut(rest, "#")
+		l.fields = append(l.fields, f)
+
+		if f.path == "" {
+			l.reject(f, "the path is empty")
+			continue
+		}
+
+		if scheme != referenceSchemeTransitDecrypt {
+			continue
+		}
+
+		f.source = f.v
+		if f.key != "" {
+			f.source = v.FieldByName(f.key)
+			if !f.source.IsValid() {
+				l.reject(f, "the ciphertext field %s does not exist", f.key)
+				continue
+			}
+		}
+		if f.source.Kind() != reflect.String {
+			l.reject(f, "the ciphertext field must be a string")
+		}
+	}
+}
+
+// collectNested collects the fields of a nested struct, or of the struct a non-nil pointer points to.
+// Self-referential structs are only collected once, so a pointer cycle does not recurse forever.
+func (l *loader) collectNested(name string, v reflect.Value) {
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Struct && v.Type() != durationType && !l.walking[v.Type()] {
+		l.collect(name, v)
+	}
+}
+
+// valid returns the fields of the given scheme whose reference is well formed.
+func (l *loader) valid(scheme string) []*loadField {
+	fields := make([]*loadField, 0, len(l.fields))
+	for _, f := range l.fields {
+		if !f.invalid && f.scheme == scheme {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// loadKv reads every referenced KV secret once and sets the fields referencing them.
+func (l *loader) loadKv(ctx context.Context) {
+	fields := l.valid(referenceSchemeKv)
+
+	paths := make([]string, 0, len(fields))
+	for _, f := range fields {
+		paths = append(paths, f.path)
+	}
+
+	var pathOpts []PathOption
+	if l.opts.kvMount != "" {
+		pathOpts = append(pathOpts, WithMount(l.opts.kvMount))
+	}
+
+	results := resolveConcurrently(paths, l.opts.concurrency, func(path string) kvResult {
+		secret, err := l.client.Path(path, pathOpts...).GetKvSecretV2(ctx)
+		if err != nil {
+			return kvResult{err: err}
+		}
+		return kvResult{data: secret.Data}
+	})
+
+	for _, f := range fields {
+		res := results[f.path]
+		if res.err != nil {
+			l.fail(f, res.err)
+			continue
+		}
+
+		if f.key == "" {
+			l.set(f, res.data)
+			continue
+		}
+
+		raw, ok := res.data[f.key]
+		if !ok || raw == nil {
+			l.fail(f, fmt.Errorf("%w: %s", ErrMissingKey, f.key))
+			continue
+		}
+		l.set(f, raw)
+	}
+}
+
+// loadTransit decrypts every referenced ciphertext once and sets the fields referencing them.
+func (l *loader) loadTransit(ctx context.Context) {
+	fields := l.valid(referenceSchemeTransitDecrypt)
+
+	inputs := make([]transitInput, 0, len(fields))
+	for _, f := range fields {
+		inputs = append(inputs, transitInput{key: f.path, ciphertext: f.source.String()})
+	}
+
+	results := resolveConcurrently(inputs, l.opts.concurrency, func(in transitInput) transitResult {
+		if in.ciphertext == "" {
+			return transitResult{err: errors.New("the ciphertext is empty")}
+		}
+
+		plaintext, err := l.client.Path(in.key, WithPrefix(l.opts.transitMount)).TransitDecrypt(ctx, in.ciphertext)
+		return transitResult{plaintext: plaintext, err: err}
+	})
+
+	for i, f := range fields {
+		res := results[inputs[i]]
+		if res.err != nil {
+			l.fail(f, res.err)
+			continue
+		}
+		l.set(f, res.plaintext)
+	}
+}
+
+// set converts the raw value to the type of the field and sets it.
+func (l *loader) set(f *loadField, raw any) {
+	d := new(decoder)
+	d.decode("", raw, f.v)
+
+	for _, err := range d.errs {
+		// Errors for the field itself rather than a key nested in it need no key.
+		var keyErr *KeyError
+		if errors.As(err, &keyErr) && keyErr.Key == "" {
+			err = keyErr.Err
+		}
+		l.fail(f, err)
+	}
+}
+
+// resolveConcurrently calls resolve once for every distinct input, running at most concurrency calls at a time.
+func resolveConcurrently[K comparable, R any](inputs []K, concurrency int, resolve func(K) R) map[K]R {
+	unique := make(map[K]struct{}, len(inputs))
+	for _, in := range inputs {
+		unique[in] = struct{}{}
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, concurrency)
+		results = make(map[K]R, len(unique))
+	)
+	for in := range unique {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			res := resolve(in)
+			<-sem
+
+			mu.Lock()
+			results[in] = res
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,49 @@
+package vaulty
+
+// LoadOption is a function that configures a Load.
+type LoadOption func(o *loadOptions)
+
+// loadOptions holds the settings of a Load.
+type loadOptions struct {
+	concurrency  int
+	kvMount      string
+	transitMount string
+}
+
+// newLoadOptions returns the load options with the options applied.
+func newLoadOptions(opts ...LoadOption) *loadOptions {
+	o := &loadOptions{
+		concurrency:  defaultLoadConcurrency,
+		kvMount:      "",
+		transitMount: defaultMountTransit,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	o.concurrency = max(o.concurrency, 1)
+
+	return o
+}
+
+// WithLoadConcurrency sets how many secrets are read at the same time. Defaults to 8.
+func WithLoadConcurrency(n int) LoadOption {
+	return func(o *loadOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithLoadKvMount sets the KV v2 mount that kv references are read from. Defaults to the mount of the client.
+func WithLoadKvMount(mount string) LoadOption {
+	return func(o *loadOptions) {
+		o.kvMount = mount
+	}
+}
+
+// WithLoadTransitMount sets the mount of the transit engine that transit-decrypt references use. Defaults to "transit".
+func WithLoadTransitMount(mount string) LoadOption {
+	return func(o *loadOptions) {
+		o.transitMount = mount
+	}
+}
@@ -0,0 +1,194 @@
+package vaulty
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLoadClient returns a client serving the given KV v2 secrets and a transit engine whose plaintexts are the
+// ciphertexts without their "vault:v1:" prefix. It counts the reads of every secret and ciphertext.
+func newTestLoadClient(t *testing.T, secrets map[string]map[string]any, reads map[string]int) Client {
+	t.Helper()
+
+	var mu sync.Mutex
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/secret/data/{path...}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		reads[r.PathValue("path")]++
+		mu.Unlock()
+
+		data, ok := secrets[r.PathValue("path")]
+		if !ok {
+			writeTestVaultError(t, w, http.StatusNotFound, "")
+			return
+		}
+		writeTestJSON(t, w, map[string]any{
+			"data": map[string]any{
+				"data":     data,
+				"metadata": kvVersionResponse(1)["data"],
+			},
+		})
+	})
+	mux.HandleFunc("PUT /v1/transit/decrypt/{key}", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Ciphertext string `json:"ciphertext"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		mu.Lock()
+		reads[r.PathValue("key")+":"+body.Ciphertext]++
+		mu.Unlock()
+
+		plaintext := body.Ciphertext[len("vault:v1:"):]
+		writeTestJSON(t, w, map[string]any{
+			"data": map[string]any{TransitKeyPlainText: base64.StdEncoding.EncodeToString([]byte(plaintext))},
+		})
+	})
+
+	return newTestPathClient(t, mux, WithKvv2Mount("secret"))
+}
+
+type testPaymentsConfig struct {
+	Port          int
+	StripeKey     string             `vault:"kv:apps/payments#stripe_key"`
+	WebhookSecret string             `vault:"kv:apps/payments#webhook_secret"`
+	Database      testDatabaseSecret `vault:"kv:apps/db"`
+	Limits        struct {
+		MaxRetries int `vault:"kv:apps/payments#max_retries"`
+	}
+	CardCipher string `vault:"kv:apps/payments#card_cipher"`
+	Card       string `vault:"transit-decrypt:payments#CardCipher"`
+	Token      string `vault:"transit-decrypt:payments"`
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	reads := make(map[string]int)
+	vc := newTestLoadClient(t, map[string]map[string]any{
+		"apps/payments": {
+			"stripe_key":     "sk_test",
+			"webhook_secret": "whsec",
+			"max_retries":    "5",
+			"card_cipher":    "vault:v1:4242",
+		},
+		"apps/db": {
+			"host":     "db.internal",
+			"port":     5432,
+			"username": "payments",
+			"password": "secret",
+		},
+	}, reads)
+
+	cfg := testPaymentsConfig{
+		Port:  8080,
+		Token: "vault:v1:api-token",
+	}
+	require.NoError(t, Load(context.Background(), vc, &cfg, WithLoadConcurrency(2)))
+
+	want := testPaymentsConfig{
+		Port:          8080,
+		StripeKey:     "sk_test",
+		WebhookSecret: "whsec",
+		Database: testDatabaseSecret{
+			Host:     "db.internal",
+			Port:     5432,
+			Username: "payments",
+			Password: "secret",
+		},
+		CardCipher: "vault:v1:4242",
+		Card:       "4242",
+		Token:      "api-token",
+	}
+	want.Limits.MaxRetries = 5
+	require.Equal(t, want, cfg)
+
+	require.Equal(t, map[string]int{
+		"apps/payments":               1,
+		"apps/db":                     1,
+		"payments:vault:v1:4242":      1,
+		"payments:vault:v1:api-token": 1,
+	}, reads)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Parallel()
+
+	vc := newTestLoadClient(t, map[string]map[string]any{
+		"apps/payments": {"stripe_key": "sk_test", "max_retries": "many"},
+	}, make(map[string]int))
+
+	var cfg struct {
+		StripeKey  string             `vault:"kv:apps/payments#stripe_key"`
+		Webhook    string             `vault:"kv:apps/payments#webhook_secret"`
+		MaxRetries int                `vault:"kv:apps/payments#max_retries"`
+		Database   testDatabaseSecret `vault:"kv:apps/db"`
+		Empty      string             `vault:"kv:#key"`
+		Card       string             `vault:"transit-decrypt:payments#Missing"`
+		Token      string             `vault:"transit-decrypt:payments"`
+		Plain      string             `vault:"plain"`
+	}
+	err := Load(context.Background(), vc, &cfg)
+	require.ErrorIs(t, err, ErrSecretNotFound)
+	require.ErrorIs(t, err, ErrMissingKey)
+	require.ErrorIs(t, err, ErrInvalidValue)
+	require.ErrorIs(t, err, ErrInvalidReference)
+	require.Equal(t, "sk_test", cfg.StripeKey)
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	require.True(t, ok)
+
+	var fields []string
+	for _, e := range joined.Unwrap() {
+		var refErr *ReferenceError
+		require.ErrorAs(t, e, &refErr)
+		fields = append(fields, refErr.Field)
+	}
+	require.ElementsMatch(t, []string{"Webhook", "MaxRetries", "Database", "Empty", "Card", "Token"}, fields)
+	require.ErrorContains(t, err, `field MaxRetries (kv:apps/payments#max_retries): invalid value: cannot convert "many" to int`)
+	require.ErrorContains(t, err, "field Webhook (kv:apps/payments#webhook_secret): required key is missing: webhook_secret")
+}
+
+func TestLoad_InvalidTarget(t *testing.T) {
+	t.Parallel()
+
+	var cfg testPaymentsConfig
+	require.ErrorContains(t, Load(context.Background(), nil, cfg), "must be a non-nil pointer to a struct")
+	require.ErrorContains(t, Load(context.Background(), nil, &cfg), "client is nil")
+}
+
+type testNodeConfig struct {
+	Token   string `vault:"kv:apps/node#token"`
+	Next    *testNodeConfig
+	Primary *testNodeEndpoint
+	Replica *testNodeEndpoint
+}
+
+type testNodeEndpoint struct {
+	Password string `vault:"kv:apps/node#password"`
+}
+
+func TestLoad_SelfReferential(t *testing.T) {
+	t.Parallel()
+
+	vc := newTestLoadClient(t, map[string]map[string]any{
+		"apps/node": {"token": "node-token", "password": "node-password"},
+	}, make(map[string]int))
+
+	cfg := &testNodeConfig{
+		Primary: &testNodeEndpoint{},
+		Replica: &testNodeEndpoint{},
+	}
+	cfg.Next = cfg
+	require.NoError(t, Load(context.Background(), vc, cfg))
+
+	require.Equal(t, "node-token", cfg.Token)
+	require.Equal(t, "node-password", cfg.Primary.Password)
+	require.Equal(t, "node-password", cfg.Replica.Password)
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockAWSCredentialsProvider is an autogenerated mock type for the AWSCredentialsProvider type
+type MockAWSCredentialsProvider struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: ctx
+func (_m *MockAWSCredentialsProvider) Execute(ctx context.Context) (*AWSCredentials, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 *AWSCredentials
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*AWSCredentials, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *AWSCredentials); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*AWSCredentials)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockAWSCredentialsProvider creates a new instance of MockAWSCredentialsProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAWSCredentialsProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAWSCredentialsProvider {
+	mock := &MockAWSCredentialsProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
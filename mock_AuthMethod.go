@@ -0,0 +1,96 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import (
+	context "context"
+
+	api "github.com/hashicorp/vault/api"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockAuthMethod is an autogenerated mock type for the AuthMethod type
+type MockAuthMethod struct {
+	mock.Mock
+}
+
+// Login provides a mock function with given fields: ctx, client
+func (_m *MockAuthMethod) Login(ctx context.Context, client *api.Client) (*api.Secret, error) {
+	ret := _m.Called(ctx, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 *api.Secret
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *api.Client) (*api.Secret, error)); ok {
+		return rf(ctx, client)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *api.Client) *api.Secret); ok {
+		r0 = rf(ctx, client)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.Secret)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *api.Client) error); ok {
+		r1 = rf(ctx, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Name provides a mock function with no fields
+func (_m *MockAuthMethod) Name() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Name")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// Renewable provides a mock function with no fields
+func (_m *MockAuthMethod) Renewable() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Renewable")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// NewMockAuthMethod creates a new instance of MockAuthMethod. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuthMethod(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuthMethod {
+	mock := &MockAuthMethod{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
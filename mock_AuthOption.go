@@ -0,0 +1,29 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockAuthOption is an autogenerated mock type for the AuthOption type
+type MockAuthOption struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: o
+func (_m *MockAuthOption) Execute(o *authOptions) {
+	_m.Called(o)
+}
+
+// NewMockAuthOption creates a new instance of MockAuthOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuthOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuthOption {
+	mock := &MockAuthOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,7 +3,10 @@
 package vaulty
 
 import (
+	context "context"
+
 	api "github.com/hashicorp/vault/api"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -32,6 +35,24 @@ func (_m *MockClient) Client() *api.Client {
 	return r0
 }
 
+// Close provides a mock function with given fields: ctx
+func (_m *MockClient) Close(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Path provides a mock function with given fields: name, opts
 func (_m *MockClient) Path(name string, opts ...PathOption) Repository {
 	_va := make([]interface{}, len(opts))
@@ -0,0 +1,52 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockCredentialSource is an autogenerated mock type for the CredentialSource type
+type MockCredentialSource struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with no fields
+func (_m *MockCredentialSource) Execute() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockCredentialSource creates a new instance of MockCredentialSource. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCredentialSource(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCredentialSource {
+	mock := &MockCredentialSource{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,29 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockLoadOption is an autogenerated mock type for the LoadOption type
+type MockLoadOption struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: o
+func (_m *MockLoadOption) Execute(o *loadOptions) {
+	_m.Called(o)
+}
+
+// NewMockLoadOption creates a new instance of MockLoadOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLoadOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLoadOption {
+	mock := &MockLoadOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -15,6 +15,182 @@ type MockRepository struct {
 	mock.Mock
 }
 
+// DeleteKvSecretV1 provides a mock function with given fields: ctx
+func (_m *MockRepository) DeleteKvSecretV1(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteKvSecretV1")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteKvSecretV2 provides a mock function with given fields: ctx, versions
+func (_m *MockRepository) DeleteKvSecretV2(ctx context.Context, versions ...uint) error {
+	_va := make([]interface{}, len(versions))
+	for _i := range versions {
+		_va[_i] = versions[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteKvSecretV2")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...uint) error); ok {
+		r0 = rf(ctx, versions...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DestroyKvSecretV2 provides a mock function with given fields: ctx, versions
+func (_m *MockRepository) DestroyKvSecretV2(ctx context.Context, versions ...uint) error {
+	_va := make([]interface{}, len(versions))
+	for _i := range versions {
+		_va[_i] = versions[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DestroyKvSecretV2")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...uint) error); ok {
+		r0 = rf(ctx, versions...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetInto provides a mock function with given fields: ctx, v
+func (_m *MockRepository) GetInto(ctx context.Context, v interface{}) error {
+	ret := _m.Called(ctx, v)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInto")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}) error); ok {
+		r0 = rf(ctx, v)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetKv provides a mock function with given fields: ctx
+func (_m *MockRepository) GetKv(ctx context.Context) (*api.KVSecret, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetKv")
+	}
+
+	var r0 *api.KVSecret
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*api.KVSecret, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *api.KVSecret); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.KVSecret)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetKvMetadataV2 provides a mock function with given fields: ctx
+func (_m *MockRepository) GetKvMetadataV2(ctx context.Context) (*api.KVMetadata, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetKvMetadataV2")
+	}
+
+	var r0 *api.KVMetadata
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*api.KVMetadata, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *api.KVMetadata); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.KVMetadata)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetKvSecretV1 provides a mock function with given fields: ctx
+func (_m *MockRepository) GetKvSecretV1(ctx context.Context) (*api.KVSecret, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetKvSecretV1")
+	}
+
+	var r0 *api.KVSecret
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*api.KVSecret, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *api.KVSecret); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.KVSecret)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetKvSecretV2 provides a mock function with given fields: ctx
 func (_m *MockRepository) GetKvSecretV2(ctx context.Context) (*api.KVSecret, error) {
 	ret := _m.Called(ctx)
@@ -45,6 +221,36 @@ func (_m *MockRepository) GetKvSecretV2(ctx context.Context) (*api.KVSecret, err
 	return r0, r1
 }
 
+// GetKvVersionsV2 provides a mock function with given fields: ctx
+func (_m *MockRepository) GetKvVersionsV2(ctx context.Context) ([]api.KVVersionMetadata, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetKvVersionsV2")
+	}
+
+	var r0 []api.KVVersionMetadata
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]api.KVVersionMetadata, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []api.KVVersionMetadata); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]api.KVVersionMetadata)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSecret provides a mock function with given fields: ctx
 func (_m *MockRepository) GetSecret(ctx context.Context) (*api.Secret, error) {
 	ret := _m.Called(ctx)
@@ -75,6 +281,258 @@ func (_m *MockRepository) GetSecret(ctx context.Context) (*api.Secret, error) {
 	return r0, r1
 }
 
+// List provides a mock function with given fields: ctx
+func (_m *MockRepository) List(ctx context.Context) ([]string, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListKvV1 provides a mock function with given fields: ctx
+func (_m *MockRepository) ListKvV1(ctx context.Context) ([]string, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListKvV1")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchKvMetadataV2 provides a mock function with given fields: ctx, metadata
+func (_m *MockRepository) PatchKvMetadataV2(ctx context.Context, metadata api.KVMetadataPatchInput) error {
+	ret := _m.Called(ctx, metadata)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchKvMetadataV2")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, api.KVMetadataPatchInput) error); ok {
+		r0 = rf(ctx, metadata)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PatchKvSecretV2 provides a mock function with given fields: ctx, data
+func (_m *MockRepository) PatchKvSecretV2(ctx context.Context, data map[string]interface{}) (*api.KVVersionMetadata, error) {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchKvSecretV2")
+	}
+
+	var r0 *api.KVVersionMetadata
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}) (*api.KVVersionMetadata, error)); ok {
+		return rf(ctx, data)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}) *api.KVVersionMetadata); ok {
+		r0 = rf(ctx, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.KVVersionMetadata)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, map[string]interface{}) error); ok {
+		r1 = rf(ctx, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PutFrom provides a mock function with given fields: ctx, v
+func (_m *MockRepository) PutFrom(ctx context.Context, v interface{}) (*api.KVVersionMetadata, error) {
+	ret := _m.Called(ctx, v)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutFrom")
+	}
+
+	var r0 *api.KVVersionMetadata
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}) (*api.KVVersionMetadata, error)); ok {
+		return rf(ctx, v)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}) *api.KVVersionMetadata); ok {
+		r0 = rf(ctx, v)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.KVVersionMetadata)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}) error); ok {
+		r1 = rf(ctx, v)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PutKv provides a mock function with given fields: ctx, data
+func (_m *MockRepository) PutKv(ctx context.Context, data map[string]interface{}) error {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutKv")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PutKvMetadataV2 provides a mock function with given fields: ctx, metadata
+func (_m *MockRepository) PutKvMetadataV2(ctx context.Context, metadata api.KVMetadataPutInput) error {
+	ret := _m.Called(ctx, metadata)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutKvMetadataV2")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, api.KVMetadataPutInput) error); ok {
+		r0 = rf(ctx, metadata)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PutKvSecretV1 provides a mock function with given fields: ctx, data
+func (_m *MockRepository) PutKvSecretV1(ctx context.Context, data map[string]interface{}) error {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutKvSecretV1")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PutKvSecretV2 provides a mock function with given fields: ctx, data
+func (_m *MockRepository) PutKvSecretV2(ctx context.Context, data map[string]interface{}) (*api.KVVersionMetadata, error) {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutKvSecretV2")
+	}
+
+	var r0 *api.KVVersionMetadata
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}) (*api.KVVersionMetadata, error)); ok {
+		return rf(ctx, data)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}) *api.KVVersionMetadata); ok {
+		r0 = rf(ctx, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.KVVersionMetadata)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, map[string]interface{}) error); ok {
+		r1 = rf(ctx, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RollbackKvSecretV2 provides a mock function with given fields: ctx
+func (_m *MockRepository) RollbackKvSecretV2(ctx context.Context) (*api.KVVersionMetadata, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RollbackKvSecretV2")
+	}
+
+	var r0 *api.KVVersionMetadata
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*api.KVVersionMetadata, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *api.KVVersionMetadata); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.KVVersionMetadata)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // TransitDecrypt provides a mock function with given fields: ctx, data
 func (_m *MockRepository) TransitDecrypt(ctx context.Context, data string) (string, error) {
 	ret := _m.Called(ctx, data)
@@ -133,6 +591,56 @@ func (_m *MockRepository) TransitEncrypt(ctx context.Context, data string) (*api
 	return r0, r1
 }
 
+// UndeleteKvSecretV2 provides a mock function with given fields: ctx, versions
+func (_m *MockRepository) UndeleteKvSecretV2(ctx context.Context, versions ...uint) error {
+	_va := make([]interface{}, len(versions))
+	for _i := range versions {
+		_va[_i] = versions[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UndeleteKvSecretV2")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...uint) error); ok {
+		r0 = rf(ctx, versions...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Walk provides a mock function with given fields: ctx, fn, opts
+func (_m *MockRepository) Walk(ctx context.Context, fn WalkFunc, opts ...WalkOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, fn)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Walk")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, WalkFunc, ...WalkOption) error); ok {
+		r0 = rf(ctx, fn, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewMockRepository creates a new instance of MockRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockRepository(t interface {
@@ -0,0 +1,111 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import (
+	context "context"
+
+	viper "github.com/spf13/viper"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockResolver is an autogenerated mock type for the Resolver type
+type MockResolver struct {
+	mock.Mock
+}
+
+// Resolve provides a mock function with given fields: ctx, value
+func (_m *MockResolver) Resolve(ctx context.Context, value string) (string, error) {
+	ret := _m.Called(ctx, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resolve")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, value)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, value)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResolveEnv provides a mock function with given fields: ctx
+func (_m *MockResolver) ResolveEnv(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveEnv")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResolveMap provides a mock function with given fields: ctx, m
+func (_m *MockResolver) ResolveMap(ctx context.Context, m map[string]interface{}) error {
+	ret := _m.Called(ctx, m)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveMap")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}) error); ok {
+		r0 = rf(ctx, m)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResolveViper provides a mock function with given fields: ctx, vip
+func (_m *MockResolver) ResolveViper(ctx context.Context, vip *viper.Viper) error {
+	ret := _m.Called(ctx, vip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveViper")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *viper.Viper) error); ok {
+		r0 = rf(ctx, vip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockResolver creates a new instance of MockResolver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockResolver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockResolver {
+	mock := &MockResolver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,29 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockResolverOption is an autogenerated mock type for the ResolverOption type
+type MockResolverOption struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: r
+func (_m *MockResolverOption) Execute(r *resolver) {
+	_m.Called(r)
+}
+
+// NewMockResolverOption creates a new instance of MockResolverOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockResolverOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockResolverOption {
+	mock := &MockResolverOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,69 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockViperSource is an autogenerated mock type for the ViperSource type
+type MockViperSource struct {
+	mock.Mock
+}
+
+// Load provides a mock function with given fields: ctx
+func (_m *MockViperSource) Load(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Load")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OnConfigChange provides a mock function with given fields: fn
+func (_m *MockViperSource) OnConfigChange(fn func(ConfigChange)) {
+	_m.Called(fn)
+}
+
+// Watch provides a mock function with given fields: ctx
+func (_m *MockViperSource) Watch(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Watch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockViperSource creates a new instance of MockViperSource. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockViperSource(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockViperSource {
+	mock := &MockViperSource{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
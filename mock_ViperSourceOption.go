@@ -0,0 +1,29 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockViperSourceOption is an autogenerated mock type for the ViperSourceOption type
+type MockViperSourceOption struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: o
+func (_m *MockViperSourceOption) Execute(o *viperSourceOptions) {
+	_m.Called(o)
+}
+
+// NewMockViperSourceOption creates a new instance of MockViperSourceOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockViperSourceOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockViperSourceOption {
+	mock := &MockViperSourceOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
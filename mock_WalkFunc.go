@@ -0,0 +1,42 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockWalkFunc is an autogenerated mock type for the WalkFunc type
+type MockWalkFunc struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: path, err
+func (_m *MockWalkFunc) Execute(path string, err error) error {
+	ret := _m.Called(path, err)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, error) error); ok {
+		r0 = rf(path, err)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockWalkFunc creates a new instance of MockWalkFunc. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockWalkFunc(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockWalkFunc {
+	mock := &MockWalkFunc{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
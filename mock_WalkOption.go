@@ -0,0 +1,29 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockWalkOption is an autogenerated mock type for the WalkOption type
+type MockWalkOption struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: o
+func (_m *MockWalkOption) Execute(o *walkOptions) {
+	_m.Called(o)
+}
+
+// NewMockWalkOption creates a new instance of MockWalkOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockWalkOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockWalkOption {
+	mock := &MockWalkOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
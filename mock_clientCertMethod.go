@@ -0,0 +1,48 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import (
+	tls "crypto/tls"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockClientCertMethod is an autogenerated mock type for the clientCertMethod type
+type mockClientCertMethod struct {
+	mock.Mock
+}
+
+// clientCertificate provides a mock function with no fields
+func (_m *mockClientCertMethod) clientCertificate() func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for clientCertificate")
+	}
+
+	var r0 func(*tls.CertificateRequestInfo) (*tls.Certificate, error)
+	if rf, ok := ret.Get(0).(func() func(*tls.CertificateRequestInfo) (*tls.Certificate, error)); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func(*tls.CertificateRequestInfo) (*tls.Certificate, error))
+		}
+	}
+
+	return r0
+}
+
+// newMockClientCertMethod creates a new instance of mockClientCertMethod. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockClientCertMethod(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockClientCertMethod {
+	mock := &mockClientCertMethod{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import (
+	context "context"
+
+	api "github.com/hashicorp/vault/api"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockKvMountClient is an autogenerated mock type for the kvMountClient type
+type mockKvMountClient struct {
+	mock.Mock
+}
+
+// kvMountVersion provides a mock function with given fields: ctx, vc, namespace, mount
+func (_m *mockKvMountClient) kvMountVersion(ctx context.Context, vc *api.Client, namespace string, mount string) (int, error) {
+	ret := _m.Called(ctx, vc, namespace, mount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for kvMountVersion")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *api.Client, string, string) (int, error)); ok {
+		return rf(ctx, vc, namespace, mount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *api.Client, string, string) int); ok {
+		r0 = rf(ctx, vc, namespace, mount)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *api.Client, string, string) error); ok {
+		r1 = rf(ctx, vc, namespace, mount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// newMockKvMountClient creates a new instance of mockKvMountClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockKvMountClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockKvMountClient {
+	mock := &mockKvMountClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,49 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import mock "github.com/stretchr/testify/mock"
+
+// mockLeaseRenewer is an autogenerated mock type for the leaseRenewer type
+type mockLeaseRenewer struct {
+	mock.Mock
+}
+
+// renewalRetryPolicy provides a mock function with no fields
+func (_m *mockLeaseRenewer) renewalRetryPolicy() *RetryPolicy {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for renewalRetryPolicy")
+	}
+
+	var r0 *RetryPolicy
+	if rf, ok := ret.Get(0).(func() *RetryPolicy); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*RetryPolicy)
+		}
+	}
+
+	return r0
+}
+
+// reportLeaseError provides a mock function with given fields: name, err
+func (_m *mockLeaseRenewer) reportLeaseError(name string, err error) {
+	_m.Called(name, err)
+}
+
+// newMockLeaseRenewer creates a new instance of mockLeaseRenewer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockLeaseRenewer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockLeaseRenewer {
+	mock := &mockLeaseRenewer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
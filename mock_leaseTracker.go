@@ -0,0 +1,78 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import (
+	context "context"
+
+	api "github.com/hashicorp/vault/api"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockLeaseTracker is an autogenerated mock type for the leaseTracker type
+type mockLeaseTracker struct {
+	mock.Mock
+}
+
+// trackLease provides a mock function with given fields: ctx, secret
+func (_m *mockLeaseTracker) trackLease(ctx context.Context, secret *api.Secret) (context.Context, *trackedLease, func(), error) {
+	ret := _m.Called(ctx, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for trackLease")
+	}
+
+	var r0 context.Context
+	var r1 *trackedLease
+	var r2 func()
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, *api.Secret) (context.Context, *trackedLease, func(), error)); ok {
+		return rf(ctx, secret)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *api.Secret) context.Context); ok {
+		r0 = rf(ctx, secret)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(context.Context)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *api.Secret) *trackedLease); ok {
+		r1 = rf(ctx, secret)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*trackedLease)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *api.Secret) func()); ok {
+		r2 = rf(ctx, secret)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(func())
+		}
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, *api.Secret) error); ok {
+		r3 = rf(ctx, secret)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// newMockLeaseTracker creates a new instance of mockLeaseTracker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockLeaseTracker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockLeaseTracker {
+	mock := &mockLeaseTracker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,33 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import (
+	slog "log/slog"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockLoggingMethod is an autogenerated mock type for the loggingMethod type
+type mockLoggingMethod struct {
+	mock.Mock
+}
+
+// setLogger provides a mock function with given fields: l
+func (_m *mockLoggingMethod) setLogger(l *slog.Logger) {
+	_m.Called(l)
+}
+
+// newMockLoggingMethod creates a new instance of mockLoggingMethod. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockLoggingMethod(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockLoggingMethod {
+	mock := &mockLoggingMethod{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,47 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import (
+	api "github.com/hashicorp/vault/api"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockNamespacedClient is an autogenerated mock type for the namespacedClient type
+type mockNamespacedClient struct {
+	mock.Mock
+}
+
+// withNamespace provides a mock function with given fields: namespace
+func (_m *mockNamespacedClient) withNamespace(namespace string) *api.Client {
+	ret := _m.Called(namespace)
+
+	if len(ret) == 0 {
+		panic("no return value specified for withNamespace")
+	}
+
+	var r0 *api.Client
+	if rf, ok := ret.Get(0).(func(string) *api.Client); ok {
+		r0 = rf(namespace)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.Client)
+		}
+	}
+
+	return r0
+}
+
+// newMockNamespacedClient creates a new instance of mockNamespacedClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockNamespacedClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockNamespacedClient {
+	mock := &mockNamespacedClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,34 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package vaulty
+
+import (
+	context "context"
+	slog "log/slog"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockWatchingMethod is an autogenerated mock type for the watchingMethod type
+type mockWatchingMethod struct {
+	mock.Mock
+}
+
+// watchCredentials provides a mock function with given fields: ctx, l, relogin
+func (_m *mockWatchingMethod) watchCredentials(ctx context.Context, l *slog.Logger, relogin func()) {
+	_m.Called(ctx, l, relogin)
+}
+
+// newMockWatchingMethod creates a new instance of mockWatchingMethod. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockWatchingMethod(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockWatchingMethod {
+	mock := &mockWatchingMethod{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -23,3 +23,20 @@ func WithMount(mount string) PathOption {
 		p.mount = mount
 	}
 }
+
+// WithCheckAndSet makes writes to the secret succeed only if its current version is the given version.
+// A version of 0 only allows the write if the secret does not exist yet.
+func WithCheckAndSet(version uint) PathOption {
+	return func(p *SecretPath) {
+		p.checkAndSet = &version
+	}
+}
+
+// WithPathNamespace sets the Vault Enterprise namespace of the secret path, overriding the namespace of the client.
+// The namespace is given in full, such as "org" to read a secret shared from a parent namespace of "org/team-a",
+// or is empty to read a secret from the root namespace.
+func WithPathNamespace(namespace string) PathOption {
+	return func(p *SecretPath) {
+		p.namespace = &namespace
+	}
+}
@@ -2,12 +2,26 @@ package vaulty
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	hashiVault "github.com/hashicorp/vault/api"
 )
 
+// ErrRenewalRetriesExhausted is returned when RenewLease gives up calling a failing renewal function.
+var ErrRenewalRetriesExhausted = errors.New("renewal retries exhausted")
+
+// leaseRenewer is implemented by clients that retry failed renewal functions and report the leases they give up on.
+type leaseRenewer interface {
+	// renewalRetryPolicy returns the policy the renewal function is retried with.
+	renewalRetryPolicy() *RetryPolicy
+
+	// reportLeaseError reports the error that stopped the renewal of the named lease.
+	reportLeaseError(name string, err error)
+}
+
 // renewResult is a bitmask which could contain one or more of the values below
 type renewResult uint8
 
@@ -22,8 +36,8 @@ const (
 // at some point and also needs to be renewed periodically.
 //
 // A function like this one should be run as a goroutine to avoid blocking.
-// Production applications may also need to be more tolerant of failures and
-// retry on errors rather than exiting.
+// If the renewal function fails, the error is returned to the caller, which
+// decides whether to retry or give up.
 //
 // Additionally, enterprise Vault users should be aware that due to eventual
 // consistency, the API may return unexpected errors when running Vault with
@@ -32,6 +46,15 @@ const (
 // this which are outside the scope of this code sample.
 //
 // ref: https://www.vaultproject.io/docs/enterprise/consistency#vault-1-7-mitigations
+//
+// A failing renewal function is retried with backoff, so a transient outage does not end the renewal.
+// When the client is a Client created by NewClient, the retries follow its auth retry policy and the error
+// that ends the renewal is reported to the function set by WithOnLeaseError. Other clients retry with
+// DefaultRetryPolicy.
+//
+// When the client is a Client created by NewClient, the lease is tracked by it:
+// renewal stops when the client is closed, and the lease is revoked on close
+// if the client was created with WithRevokeOnClose.
 func RenewLease(
 	ctx context.Context,
 	l *slog.Logger,
@@ -39,6 +62,74 @@ func RenewLease(
 	name string,
 	credentials *hashiVault.Secret,
 	renewFunc RenewalFunc,
+) error {
+	policy := DefaultRetryPolicy()
+	renewer, isRenewer := client.(leaseRenewer)
+	if isRenewer {
+		policy = renewer.renewalRetryPolicy()
+	}
+
+	tracker, ok := client.(leaseTracker)
+	if !ok {
+		return renewLease(ctx, l, client, name, credentials, retryRenewal(ctx, l, name, policy, renewFunc), nil)
+	}
+
+	ctx, lease, release, err := tracker.trackLease(ctx, credentials)
+	if err != nil {
+		return fmt.Errorf("unable to track lease: %w", err)
+	}
+	defer release()
+
+	err = renewLease(ctx, l, client, name, credentials, retryRenewal(ctx, l, name, policy, renewFunc), lease)
+	if err != nil && isRenewer && ctx.Err() == nil {
+		renewer.reportLeaseError(name, err)
+	}
+	return err
+}
+
+// retryRenewal returns a renewal function that calls renewFunc until it succeeds, backing off between
+// failed attempts until the retry policy is exhausted or the context is cancelled.
+func retryRenewal(ctx context.Context, l *slog.Logger, name string, policy *RetryPolicy, renewFunc RenewalFunc) RenewalFunc {
+	return func() (*hashiVault.Secret, error) {
+		start := time.Now()
+
+		for attempt := 1; ; attempt++ {
+			secret, err := renewFunc()
+			if err == nil {
+				return secret, nil
+			}
+
+			l.Warn("renewal attempt failed",
+				slog.String(loggingKeySecretName, name),
+				slog.Int(loggingKeyAttempt, attempt),
+				slog.String(loggingKeyError, err.Error()),
+			)
+
+			if policy.exhausted(attempt, time.Since(start)) {
+				return nil, fmt.Errorf("%w after %d attempts: %w", ErrRenewalRetriesExhausted, attempt, err)
+			}
+
+			timer := time.NewTimer(policy.backoff(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, fmt.Errorf("renewal retry cancelled: %w", ctx.Err())
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+// renewLease renews the lease until the context is cancelled or renewal fails,
+// keeping the tracked lease, if any, up to date with the current credentials.
+func renewLease(
+	ctx context.Context,
+	l *slog.Logger,
+	client ClientHandler,
+	name string,
+	credentials *hashiVault.Secret,
+	renewFunc RenewalFunc,
+	lease *trackedLease,
 ) error {
 	l.Debug("renewing lease", slog.String(loggingKeySecretName, name))
 
@@ -54,14 +145,15 @@ func RenewLease(
 			return nil
 		}
 
-		err = handleWatcherResult(l, res, func() {
+		err = handleWatcherResult(l, res, func() error {
 			newCreds, err := renewFunc()
 			if err != nil {
-				l.Error("unable to renew credentials", slog.String(loggingKeyError, err.Error()))
-				panic("unable to renew credentials") // Panic over os exit. This allows for the program unwind correctly.
+				return fmt.Errorf("unable to renew credentials: %w", err)
 			}
 
 			currentCreds = newCreds
+			lease.set(newCreds)
+			return nil
 		})
 		if err != nil {
 			return fmt.Errorf("unable to handle watcher result: %w", err)
@@ -0,0 +1,90 @@
+package vaulty
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	hashiVault "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/require"
+)
+
+// expiredLease returns a lease that the lifetime watcher treats as already expired,
+// as long as the server does not renew it.
+func expiredLease(leaseID string) *hashiVault.Secret {
+	return &hashiVault.Secret{
+		LeaseID:       leaseID,
+		Renewable:     true,
+		LeaseDuration: 0,
+	}
+}
+
+func TestRenewLease_Retries(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries a failing renewal function", func(t *testing.T) {
+		t.Parallel()
+
+		c := newTestClient(t, nil)
+		require.NoError(t, c.v.SetAddress(newTestVaultServer(t, http.NewServeMux()).URL))
+
+		var leaseErrs atomic.Int64
+		c.onLeaseError = func(string, error) {
+			leaseErrs.Add(1)
+		}
+
+		var calls atomic.Int64
+		ctx, cancel := context.WithCancel(t.Context())
+		done := make(chan error, 1)
+		go func() {
+			done <- RenewLease(ctx, c.l, c, "database", expiredLease("database/creds/app/1"), func() (*hashiVault.Secret, error) {
+				if calls.Add(1) == 1 {
+					return nil, errors.New("vault is unavailable")
+				}
+				return &hashiVault.Secret{
+					LeaseID:       "database/creds/app/2",
+					Renewable:     false,
+					LeaseDuration: 3600,
+				}, nil
+			})
+		}()
+
+		require.Eventually(t, func() bool {
+			return calls.Load() == 2
+		}, 5*time.Second, time.Millisecond)
+
+		cancel()
+		require.NoError(t, <-done)
+		require.Equal(t, int64(2), calls.Load())
+		require.Zero(t, leaseErrs.Load())
+	})
+
+	t.Run("reports the error when the retry policy is exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		c := newTestClient(t, nil)
+		require.NoError(t, c.v.SetAddress(newTestVaultServer(t, http.NewServeMux()).URL))
+		c.retryPolicy.MaxAttempts = 3
+
+		var (
+			leaseName string
+			leaseErr  error
+		)
+		c.onLeaseError = func(name string, err error) {
+			leaseName, leaseErr = name, err
+		}
+
+		calls := 0
+		err := RenewLease(t.Context(), c.l, c, "database", expiredLease("database/creds/app/1"), func() (*hashiVault.Secret, error) {
+			calls++
+			return nil, errors.New("vault is unavailable")
+		})
+		require.ErrorIs(t, err, ErrRenewalRetriesExhausted)
+		require.Equal(t, 3, calls)
+		require.Equal(t, "database", leaseName)
+		require.ErrorIs(t, leaseErr, ErrRenewalRetriesExhausted)
+	})
+}
@@ -11,6 +11,66 @@ type Repository interface {
 	// GetKvSecretV2 returns a map of secrets for the given path.
 	GetKvSecretV2(ctx context.Context) (*hashiVault.KVSecret, error)
 
+	// PutKvSecretV2 writes the given data as a new version of the secret.
+	PutKvSecretV2(ctx context.Context, data map[string]any) (*hashiVault.KVVersionMetadata, error)
+
+	// PatchKvSecretV2 merges the given data into the latest version of the secret.
+	PatchKvSecretV2(ctx context.Context, data map[string]any) (*hashiVault.KVVersionMetadata, error)
+
+	// GetInto reads the secret and decodes its data into the struct v points to.
+	GetInto(ctx context.Context, v any) error
+
+	// PutFrom encodes the given struct and writes it as a new version of the secret.
+	PutFrom(ctx context.Context, v any) (*hashiVault.KVVersionMetadata, error)
+
+	// DeleteKvSecretV2 soft deletes versions of the secret, by default the latest.
+	DeleteKvSecretV2(ctx context.Context, versions ...uint) error
+
+	// UndeleteKvSecretV2 restores soft deleted versions of the secret.
+	UndeleteKvSecretV2(ctx context.Context, versions ...uint) error
+
+	// DestroyKvSecretV2 permanently removes the data of versions of the secret.
+	DestroyKvSecretV2(ctx context.Context, versions ...uint) error
+
+	// RollbackKvSecretV2 writes an older version of the secret again as the newest version.
+	RollbackKvSecretV2(ctx context.Context) (*hashiVault.KVVersionMetadata, error)
+
+	// GetKvMetadataV2 returns the metadata of the secret, including its versions and custom metadata.
+	GetKvMetadataV2(ctx context.Context) (*hashiVault.KVMetadata, error)
+
+	// GetKvVersionsV2 returns the metadata of every version of the secret, oldest first.
+	GetKvVersionsV2(ctx context.Context) ([]hashiVault.KVVersionMetadata, error)
+
+	// PutKvMetadataV2 replaces the metadata of the secret.
+	PutKvMetadataV2(ctx context.Context, metadata hashiVault.KVMetadataPutInput) error
+
+	// PatchKvMetadataV2 updates the given fields of the metadata of the secret.
+	PatchKvMetadataV2(ctx context.Context, metadata hashiVault.KVMetadataPatchInput) error
+
+	// List returns the names of the secrets and folders directly below the path.
+	List(ctx context.Context) ([]string, error)
+
+	// Walk calls fn for every secret and folder below the path.
+	Walk(ctx context.Context, fn WalkFunc, opts ...WalkOption) error
+
+	// GetKvSecretV1 returns the secret at the path of a KV v1 mount.
+	GetKvSecretV1(ctx context.Context) (*hashiVault.KVSecret, error)
+
+	// PutKvSecretV1 replaces the secret at the path of a KV v1 mount.
+	PutKvSecretV1(ctx context.Context, data map[string]any) error
+
+	// DeleteKvSecretV1 deletes the secret at the path of a KV v1 mount.
+	DeleteKvSecretV1(ctx context.Context) error
+
+	// ListKvV1 returns the names of the secrets and folders directly below the path of a KV v1 mount.
+	ListKvV1(ctx context.Context) ([]string, error)
+
+	// GetKv returns the secret at the path, detecting the KV version of the mount.
+	GetKv(ctx context.Context) (*hashiVault.KVSecret, error)
+
+	// PutKv writes the secret at the path, detecting the KV version of the mount.
+	PutKv(ctx context.Context, data map[string]any) error
+
 	// GetSecret returns a map of secrets for the given path.
 	GetSecret(ctx context.Context) (*hashiVault.Secret, error)
 
@@ -0,0 +1,342 @@
+package vaulty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// Resolver replaces secret reference URIs in config values with the secrets they reference.
+//
+// A KV v2 reference has the form vault://<mount>/<path>#<key>, optionally with a version such as
+// vault://kv/apps/api#db_password?version=3. A transit reference has the form vault+transit://<key>/<ciphertext>,
+// such as vault+transit://payments/vault:v1:abcd, and resolves to the plaintext of the ciphertext.
+//
+// Every secret and ciphertext is cached for the lifetime of the resolver once it has been read.
+type Resolver interface {
+	// Resolve returns the secret the value references, or the value unchanged if it is not a reference.
+	Resolve(ctx context.Context, value string) (string, error)
+
+	// ResolveMap replaces the references in the string values of the map and its nested maps and slices.
+	ResolveMap(ctx context.Context, m map[string]any) error
+
+	// ResolveEnv replaces the references in the values of the environment variables of the process.
+	ResolveEnv(ctx context.Context) error
+
+	// ResolveViper merges the secrets the config values of the viper instance reference into its config,
+	// so that vip.GetString returns the secret.
+	ResolveViper(ctx context.Context, vip *viper.Viper) error
+}
+
+// resolver is a struct that implements the Resolver interface.
+type resolver struct {
+	client       Client
+	transitMount string
+
+	// mu guards the secrets and plaintexts read so far. It is not held while reading from Vault.
+	mu         sync.Mutex
+	secrets    map[secretRefLocation]map[string]any
+	plaintexts map[string]string
+}
+
+// secretRefLocation is a version of a KV v2 secret.
+type secretRefLocation struct {
+	mount   string
+	path    string
+	version uint
+}
+
+// secretRef is a parsed secret reference URI. The path of a transit reference is the name of its key.
+type secretRef struct {
+	secretRefLocation
+	key string
+
+	transit    bool
+	ciphertext string
+}
+
+// NewResolver creates a new Resolver that reads the referenced secrets with the given client.
+func NewResolver(client Client, opts ...ResolverOption) Resolver {
+	r := &resolver{
+		client:       client,
+		transitMount: defaultMountTransit,
+		secrets:      make(map[secretRefLocation]map[string]any),
+		plaintexts:   make(map[string]string),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// IsSecretReference reports whether the value is a vault:// or vault+transit:// secret reference.
+func IsSecretReference(value string) bool {
+	return strings.HasPrefix(value, referenceSchemeVault) || strings.HasPrefix(value, referenceSchemeVaultTransit)
+}
+
+// Resolve returns the secret the value references, or the value unchanged if it is not a reference.
+func (r *resolver) Resolve(ctx context.Context, value string) (string, error) {
+	if !IsSecretReference(value) {
+		return value, nil
+	}
+
+	ref, err := parseSecretRef(value)
+	if err != nil {
+		return "", err
+	}
+
+	if ref.transit {
+		return r.decrypt(ctx, ref)
+	}
+	return r.read(ctx, ref)
+}
+
+// ResolveMap replaces the references in the string values of the map and its nested maps and slices.
+// Every reference that cannot be resolved is reported, as a ReferenceError naming its key, in the returned joined error.
+func (r *resolver) ResolveMap(ctx context.Context, m map[string]any) error {
+	var errs []error
+	r.resolveMap(ctx, "", m, &errs)
+	return errors.Join(errs...)
+}
+
+// ResolveEnv replaces the references in the values of the environment variables of the process.
+// Every reference that cannot be resolved is reported, as a ReferenceError naming its variable, in the returned joined error.
+func (r *resolver) ResolveEnv(ctx context.Context) error {
+	var errs []error
+	for _, env := range os.Environ() {
+		name, value, _ := strings.Cut(env, "=")
+		if !IsSecretReference(value) {
+			continue
+		}
+
+		secret, err := r.Resolve(ctx, value)
+		if err != nil {
+			errs = append(errs, &ReferenceError{Field: name, Reference: value, Err: err})
+			continue
+		}
+
+		if err := os.Setenv(name, secret); err != nil {
+			errs = append(errs, &ReferenceError{Field: name, Reference: value, Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ResolveViper merges the secrets the values of the viper instance reference into its config.
+// The secrets are replaced when the config is read again. Values set with vip.Set, flags and environment variables
+// take precedence over the config, so the references they hold are not replaced.
+// Every reference that cannot be resolved is reported, as a ReferenceError naming its key, in the returned joined error.
+func (r *resolver) ResolveViper(ctx context.Context, vip *viper.Viper) error {
+	if vip == nil {
+		return errors.New("no viper configuration provided")
+	}
+
+	var errs []error
+	merged := make(map[string]any)
+	for _, key := range vip.AllKeys() {
+		var resolved any
+		switch value := vip.Get(key).(type) {
+		case string:
+			if !IsSecretReference(value) {
+				continue
+			}
+
+			secret, err := r.Resolve(ctx, value)
+			if err != nil {
+				errs = append(errs, &ReferenceError{Field: key, Reference: value, Err: err})
+				continue
+			}
+			resolved = secret
+		case []any:
+			if !r.resolveSlice(ctx, key, value, &errs) {
+				continue
+			}
+			resolved = value
+		default:
+			continue
+		}
+
+		keys := strings.Split(key, ".")
+		mergeNested(merged, keys[:len(keys)-1], map[string]any{keys[len(keys)-1]: resolved})
+	}
+
+	if len(merged) > 0 {
+		if err := vip.MergeConfigMap(merged); err != nil {
+			errs = append(errs, fmt.Errorf("unable to merge configuration: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// resolveMap replaces the references in the map, recording an error for every reference that cannot be resolved.
+func (r *resolver) resolveMap(ctx context.Context, path string, m map[string]any, errs *[]error) {
+	for key, value := range m {
+		if resolved, ok := r.resolveValue(ctx, keyPath(path, key), value, errs); ok {
+			m[key] = resolved
+		}
+	}
+}
+
+// resolveSlice replaces the references in the slice, reporting whether any were replaced.
+func (r *resolver) resolveSlice(ctx context.Context, path string, s []any, errs *[]error) bool {
+	replaced := false
+	for i, value := range s {
+		if resolved, ok := r.resolveValue(ctx, fmt.Sprintf("%s[%d]", path, i), value, errs); ok {
+			s[i] = resolved
+			replaced = true
+		}
+	}
+	return replaced
+}
+
+// resolveValue returns the resolved value and true if the value is a reference, resolving nested maps and slices in place.
+func (r *resolver) resolveValue(ctx context.Context, path string, value any, errs *[]error) (any, bool) {
+	switch v := value.(type) {
+	case string:
+		if !IsSecretReference(v) {
+			return nil, false
+		}
+
+		secret, err := r.Resolve(ctx, v)
+		if err != nil {
+			*errs = append(*errs, &ReferenceError{Field: path, Reference: v, Err: err})
+			return nil, false
+		}
+		return secret, true
+	case map[string]any:
+		r.resolveMap(ctx, path, v, errs)
+	case []any:
+		r.resolveSlice(ctx, path, v, errs)
+	}
+	return nil, false
+}
+
+// read returns the value of the key of the KV v2 secret the reference points to, reading the secret once.
+func (r *resolver) read(ctx context.Context, ref *secretRef) (string, error) {
+	r.mu.Lock()
+	data, ok := r.secrets[ref.secretRefLocation]
+	r.mu.Unlock()
+
+	if !ok {
+		opts := []PathOption{WithMount(ref.mount)}
+		if ref.version != 0 {
+			opts = append(opts, WithVersion(ref.version))
+		}
+
+		secret, err := r.client.Path(ref.path, opts...).GetKvSecretV2(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		data = secret.Data
+		r.mu.Lock()
+		r.secrets[ref.secretRefLocation] = data
+		r.mu.Unlock()
+	}
+
+	raw, ok := data[ref.key]
+	if !ok || raw == nil {
+		return "", fmt.Errorf("%w: %s", ErrMissingKey, ref.key)
+	}
+
+	value, ok := scalarString(raw)
+	if !ok {
+		return "", fmt.Errorf("%w: the value of %s is a %T, not a string", ErrInvalidValue, ref.key, raw)
+	}
+	return value, nil
+}
+
+// decrypt returns the plaintext of the ciphertext of the transit reference, decrypting it once.
+func (r *resolver) decrypt(ctx context.Context, ref *secretRef) (string, error) {
+	cacheKey := ref.path + ":" + ref.ciphertext
+	r.mu.Lock()
+	plaintext, ok := r.plaintexts[cacheKey]
+	r.mu.Unlock()
+	if ok {
+		return plaintext, nil
+	}
+
+	plaintext, err := r.client.Path(ref.path, WithPrefix(r.transitMount)).TransitDecrypt(ctx, ref.ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	r.plaintexts[cacheKey] = plaintext
+	r.mu.Unlock()
+	return plaintext, nil
+}
+
+// parseSecretRef parses a vault:// or vault+transit:// secret reference.
+func parseSecretRef(value string) (*secretRef, error) {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w %q: %s", ErrInvalidReference, value, fmt.Sprintf(format, args...))
+	}
+
+	if rest, ok := strings.CutPrefix(value, referenceSchemeVaultTransit); ok {
+		key, ciphertext, _ := strings.Cut(rest, "/")
+		if key == "" || ciphertext == "" {
+			return nil, invalid("expected %s<key>/<ciphertext>", referenceSchemeVaultTransit)
+		}
+		return &secretRef{
+			secretRefLocation: secretRefLocation{path: key},
+			transit:           true,
+			ciphertext:        ciphertext,
+		}, nil
+	}
+
+	rest, ok := strings.CutPrefix(value, referenceSchemeVault)
+	if !ok {
+		return nil, invalid("expected a %s or %s URI", referenceSchemeVault, referenceSchemeVaultTransit)
+	}
+
+	location, key, _ := strings.Cut(rest, "#")
+
+	// The query may follow the key, as in #db_password?version=3, or come before it.
+	var query string
+	if before, after, found := strings.Cut(key, "?"); found {
+		key, query = before, after
+	} else if before, after, found := strings.Cut(location, "?"); found {
+		location, query = before, after
+	}
+
+	mount, path, _ := strings.Cut(location, "/")
+	path = strings.Trim(path, "/")
+	if mount == "" || path == "" {
+		return nil, invalid("expected %s<mount>/<path>#<key>", referenceSchemeVault)
+	} else if key == "" {
+		return nil, invalid("the key is missing, expected %s<mount>/<path>#<key>", referenceSchemeVault)
+	}
+
+	ref := &secretRef{
+		secretRefLocation: secretRefLocation{mount: mount, path: path},
+		key:               key,
+	}
+
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, invalid("unable to parse the query: %s", err)
+	}
+	for name, values := range params {
+		if name != referenceParamVersion {
+			return nil, invalid("unsupported parameter %q", name)
+		}
+
+		version, err := strconv.ParseUint(values[0], 10, 0)
+		if err != nil || version == 0 || len(values) > 1 {
+			return nil, invalid("the version must be a single positive number")
+		}
+		ref.version = uint(version)
+	}
+
+	return ref, nil
+}
@@ -0,0 +1,12 @@
+package vaulty
+
+// ResolverOption is a function that configures a Resolver.
+type ResolverOption func(r *resolver)
+
+// WithResolverTransitMount sets the mount of the transit engine that vault+transit references use.
+// Defaults to "transit".
+func WithResolverTransitMount(mount string) ResolverOption {
+	return func(r *resolver) {
+		r.transitMount = mount
+	}
+}
@@ -0,0 +1,211 @@
+package vaulty
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestResolver returns a resolver for a server holding two versions of the kv/apps/api secret and a transit
+// engine mounted at "encryption", and counts the requests sent to the server.
+func newTestResolver(t *testing.T, requests *atomic.Int32) Resolver {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/kv/data/apps/api", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		data := map[string]any{"db_password": "current", "port": 5432, "nested": map[string]any{}}
+		if r.URL.Query().Get("version") == "3" {
+			data["db_password"] = "old"
+		}
+		writeTestJSON(t, w, map[string]any{
+			"data": map[string]any{
+				"data":     data,
+				"metadata": kvVersionResponse(4)["data"],
+			},
+		})
+	})
+	mux.HandleFunc("PUT /v1/encryption/decrypt/payments", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		var body struct {
+			Ciphertext string `json:"ciphertext"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		writeTestJSON(t, w, map[string]any{
+			"data": map[string]any{
+				TransitKeyPlainText: base64.StdEncoding.EncodeToString([]byte(strings.TrimPrefix(body.Ciphertext, "vault:v1:"))),
+			},
+		})
+	})
+
+	return NewResolver(newTestPathClient(t, mux), WithResolverTransitMount("encryption"))
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	r := newTestResolver(t, &requests)
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "plain value", want: "plain value"},
+		{value: "vault://kv/apps/api#db_password", want: "current"},
+		{value: "vault://kv/apps/api#db_password?version=3", want: "old"},
+		{value: "vault://kv/apps/api?version=3#db_password", want: "old"},
+		{value: "vault://kv/apps/api#port", want: "5432"},
+		{value: "vault+transit://payments/vault:v1:card/number+1=", want: "card/number+1="},
+	}
+
+	for _, tt := range tests {
+		got, err := r.Resolve(context.Background(), tt.value)
+		require.NoError(t, err, tt.value)
+		require.Equal(t, tt.want, got, tt.value)
+	}
+
+	// Resolving the same references again is served from the cache.
+	for _, tt := range tests {
+		_, err := r.Resolve(context.Background(), tt.value)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(3), requests.Load())
+}
+
+func TestResolver_Resolve_Errors(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	r := newTestResolver(t, &requests)
+
+	tests := []struct {
+		value   string
+		wantErr error
+		wantMsg string
+	}{
+		{value: "vault://kv", wantErr: ErrInvalidReference, wantMsg: "expected vault://<mount>/<path>#<key>"},
+		{value: "vault://kv/apps/api", wantErr: ErrInvalidReference, wantMsg: "the key is missing"},
+		{value: "vault://kv/apps/api#db_password?version=0", wantErr: ErrInvalidReference, wantMsg: "positive number"},
+		{value: "vault://kv/apps/api#db_password?version=x", wantErr: ErrInvalidReference, wantMsg: "positive number"},
+		{value: "vault://kv/apps/api#db_password?ttl=1", wantErr: ErrInvalidReference, wantMsg: `unsupported parameter "ttl"`},
+		{value: "vault+transit://payments", wantErr: ErrInvalidReference, wantMsg: "expected vault+transit://<key>/<ciphertext>"},
+		{value: "vault://kv/apps/api#missing", wantErr: ErrMissingKey, wantMsg: "missing"},
+		{value: "vault://kv/apps/api#nested", wantErr: ErrInvalidValue, wantMsg: "not a string"},
+	}
+
+	for _, tt := range tests {
+		_, err := r.Resolve(context.Background(), tt.value)
+		require.ErrorIs(t, err, tt.wantErr, tt.value)
+		require.ErrorContains(t, err, tt.wantMsg, tt.value)
+	}
+}
+
+func TestResolver_ResolveMap(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	r := newTestResolver(t, &requests)
+
+	m := map[string]any{
+		"database": map[string]any{
+			"host":     "db.internal",
+			"password": "vault://kv/apps/api#db_password",
+		},
+		"tokens": []any{"vault+transit://payments/vault:v1:token", "static"},
+		"broken": "vault://kv/apps/api#missing",
+	}
+	err := r.ResolveMap(context.Background(), m)
+	require.ErrorIs(t, err, ErrMissingKey)
+	require.ErrorContains(t, err, "field broken (vault://kv/apps/api#missing)")
+	require.Equal(t, map[string]any{
+		"database": map[string]any{
+			"host":     "db.internal",
+			"password": "current",
+		},
+		"tokens": []any{"token", "static"},
+		"broken": "vault://kv/apps/api#missing",
+	}, m)
+}
+
+func TestResolver_ResolveViper(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	r := newTestResolver(t, &requests)
+
+	vip := viper.New()
+	vip.SetConfigType("yaml")
+	require.NoError(t, vip.ReadConfig(strings.NewReader(`
+database:
+  host: db.internal
+  password: vault://kv/apps/api#db_password
+  old_password: vault://kv/apps/api#db_password?version=3
+tokens:
+  - vault+transit://payments/vault:v1:token
+  - static
+`)))
+
+	require.NoError(t, r.ResolveViper(context.Background(), vip))
+	require.Equal(t, "db.internal", vip.GetString("database.host"))
+	require.Equal(t, "current", vip.GetString("database.password"))
+	require.Equal(t, "old", vip.GetString("database.old_password"))
+	require.Equal(t, []string{"token", "static"}, vip.GetStringSlice("tokens"))
+
+	// The secrets are merged into the config rather than set as overrides, so reading the config again replaces them.
+	require.NoError(t, vip.ReadConfig(strings.NewReader("database:\n  password: rotated\n")))
+	require.Equal(t, "rotated", vip.GetString("database.password"))
+}
+
+func TestResolver_Resolve_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/kv/data/apps/slow", func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		writeTestJSON(t, w, map[string]any{"data": map[string]any{"data": map[string]any{"key": "slow"}}})
+	})
+	mux.HandleFunc("GET /v1/kv/data/apps/fast", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestJSON(t, w, map[string]any{"data": map[string]any{"data": map[string]any{"key": "fast"}}})
+	})
+	r := NewResolver(newTestPathClient(t, mux))
+
+	slow := make(chan string, 1)
+	go func() {
+		value, _ := r.Resolve(context.Background(), "vault://kv/apps/slow#key")
+		slow <- value
+	}()
+
+	// A slow secret does not hold up the secrets resolved while it is being read.
+	<-started
+	value, err := r.Resolve(context.Background(), "vault://kv/apps/fast#key")
+	require.NoError(t, err)
+	require.Equal(t, "fast", value)
+
+	close(release)
+	require.Equal(t, "slow", <-slow)
+}
+
+func TestResolver_ResolveEnv(t *testing.T) {
+	var requests atomic.Int32
+	r := newTestResolver(t, &requests)
+
+	t.Setenv("VAULTY_TEST_DB_PASSWORD", "vault://kv/apps/api#db_password")
+	t.Setenv("VAULTY_TEST_PLAIN", "plain")
+
+	require.NoError(t, r.ResolveEnv(context.Background()))
+	require.Equal(t, "current", os.Getenv("VAULTY_TEST_DB_PASSWORD"))
+	require.Equal(t, "plain", os.Getenv("VAULTY_TEST_PLAIN"))
+}
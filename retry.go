@@ -0,0 +1,74 @@
+package vaulty
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy controls how the client retries logging in to Vault once its auth token can no longer be renewed,
+// and how RenewLease retries a failing renewal function.
+type RetryPolicy struct {
+	// InitialInterval is the delay before the first retry.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the delay between two retries.
+	MaxInterval time.Duration
+
+	// Multiplier is applied to the delay after every failed attempt.
+	Multiplier float64
+
+	// Jitter is the randomization factor applied to every delay, between 0 and 1.
+	// A jitter of 0.2 spreads a 10s delay over the range 8s to 12s.
+	Jitter float64
+
+	// MaxAttempts is the number of attempts made before giving up. Zero means no limit.
+	MaxAttempts int
+
+	// MaxElapsedTime is the total time spent retrying before giving up. Zero means no limit.
+	MaxElapsedTime time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured.
+// It retries forever, backing off exponentially from 1 second up to 1 minute.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		InitialInterval: time.Second,
+		MaxInterval:     time.Minute,
+		Multiplier:      2,
+		Jitter:          0.2,
+		MaxAttempts:     0,
+		MaxElapsedTime:  0,
+	}
+}
+
+// backoff returns the delay to wait after the given failed attempt, starting at 1.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	interval := float64(p.InitialInterval)
+	multiplier := max(p.Multiplier, 1)
+	for i := 1; i < attempt; i++ {
+		interval *= multiplier
+		if p.MaxInterval > 0 && interval >= float64(p.MaxInterval) {
+			interval = float64(p.MaxInterval)
+			break
+		}
+	}
+
+	if p.Jitter > 0 {
+		delta := p.Jitter * interval
+		interval = interval - delta + rand.Float64()*(2*delta) // nolint:gosec // Jitter does not need a secure random source
+	}
+
+	return time.Duration(interval)
+}
+
+// exhausted reports whether the policy allows no further attempts.
+func (p *RetryPolicy) exhausted(attempt int, elapsed time.Duration) bool {
+	switch {
+	case p.MaxAttempts > 0 && attempt >= p.MaxAttempts:
+		return true
+	case p.MaxElapsedTime > 0 && elapsed >= p.MaxElapsedTime:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,109 @@
+package vaulty
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+
+	policy := &RetryPolicy{
+		InitialInterval: time.Second,
+		MaxInterval:     10 * time.Second,
+		Multiplier:      2,
+	}
+
+	tests := []struct {
+		name    string
+		attempt int
+		want    time.Duration
+	}{
+		{
+			name:    "first attempt",
+			attempt: 1,
+			want:    time.Second,
+		},
+		{
+			name:    "third attempt",
+			attempt: 3,
+			want:    4 * time.Second,
+		},
+		{
+			name:    "capped at max interval",
+			attempt: 10,
+			want:    10 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, policy.backoff(tt.attempt))
+		})
+	}
+}
+
+func TestRetryPolicy_BackoffJitter(t *testing.T) {
+	t.Parallel()
+
+	policy := &RetryPolicy{
+		InitialInterval: 10 * time.Second,
+		Multiplier:      2,
+		Jitter:          0.2,
+	}
+
+	for range 100 {
+		got := policy.backoff(1)
+		require.GreaterOrEqual(t, got, 8*time.Second)
+		require.LessOrEqual(t, got, 12*time.Second)
+	}
+}
+
+func TestRetryPolicy_Exhausted(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		policy  *RetryPolicy
+		attempt int
+		elapsed time.Duration
+		want    bool
+	}{
+		{
+			name:    "no limits",
+			policy:  &RetryPolicy{},
+			attempt: 1000,
+			elapsed: time.Hour,
+			want:    false,
+		},
+		{
+			name:    "below max attempts",
+			policy:  &RetryPolicy{MaxAttempts: 3},
+			attempt: 2,
+			want:    false,
+		},
+		{
+			name:    "max attempts reached",
+			policy:  &RetryPolicy{MaxAttempts: 3},
+			attempt: 3,
+			want:    true,
+		},
+		{
+			name:    "max elapsed time reached",
+			policy:  &RetryPolicy{MaxElapsedTime: time.Minute},
+			attempt: 1,
+			elapsed: time.Minute,
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, tt.policy.exhausted(tt.attempt, tt.elapsed))
+		})
+	}
+}
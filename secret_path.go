@@ -5,10 +5,20 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 
 	hashiVault "github.com/hashicorp/vault/api"
 )
 
+// ErrCheckAndSetMismatch is returned when a write is rejected because the secret is not at the check-and-set version.
+var ErrCheckAndSetMismatch = errors.New("check-and-set version mismatch")
+
+// namespacedClient is implemented by clients that can send requests to another namespace.
+type namespacedClient interface {
+	withNamespace(namespace string) *hashiVault.Client
+}
+
 // SecretPath represents a path to a secret in Vault.
 type SecretPath struct {
 	client  Client
@@ -16,6 +26,25 @@ type SecretPath struct {
 	prefix  string
 	name    string
 	version uint
+
+	// namespace is the namespace requests are sent to, when it differs from the namespace of the client.
+	namespace *string
+
+	// checkAndSet is the version writes must match, when set.
+	checkAndSet *uint
+}
+
+// vaultClient returns the Vault client to send the requests for the path with.
+func (c *SecretPath) vaultClient() *hashiVault.Client {
+	if c.namespace == nil {
+		return c.client.Client()
+	}
+
+	if nc, ok := c.client.(namespacedClient); ok {
+		return nc.withNamespace(*c.namespace)
+	}
+
+	return c.client.Client().WithNamespace(*c.namespace)
 }
 
 // path returns the path of the secret.
@@ -43,7 +72,7 @@ func (c *SecretPath) GetKvSecretV2(ctx context.Context) (*hashiVault.KVSecret, e
 		return nil, fmt.Errorf("incompatible version: %w", err)
 	}
 
-	secret, err := c.client.Client().KVv2(c.mount).GetVersion(ctx, c.path(), version)
+	secret, err := c.vaultClient().KVv2(c.mount).GetVersion(ctx, c.path(), version)
 	if err != nil {
 		return nil, fmt.Errorf("unable to read secret: %w", err)
 	} else if secret == nil {
@@ -52,9 +81,198 @@ func (c *SecretPath) GetKvSecretV2(ctx context.Context) (*hashiVault.KVSecret, e
 	return secret, nil
 }
 
+// PutKvSecretV2 writes the given data as a new version of the secret and returns the metadata of that version.
+// When the path has a check-and-set version, a write that does not match it returns ErrCheckAndSetMismatch.
+func (c *SecretPath) PutKvSecretV2(ctx context.Context, data map[string]any) (*hashiVault.KVVersionMetadata, error) {
+	opts, err := c.kvWriteOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := c.vaultClient().KVv2(c.mount).Put(ctx, c.path(), data, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to write secret: %w", checkAndSetError(err))
+	}
+	return secret.VersionMetadata, nil
+}
+
+// PatchKvSecretV2 applies the given data to the latest version of the secret as a JSON merge patch,
+// writing a new version, and returns the metadata of that version. Keys set to nil are removed.
+// When the path has a check-and-set version, a patch that does not match it returns ErrCheckAndSetMismatch.
+func (c *SecretPath) PatchKvSecretV2(ctx context.Context, data map[string]any) (*hashiVault.KVVersionMetadata, error) {
+	opts, err := c.kvWriteOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := c.vaultClient().KVv2(c.mount).Patch(ctx, c.path(), data, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to patch secret: %w", checkAndSetError(err))
+	}
+	return secret.VersionMetadata, nil
+}
+
+// DeleteKvSecretV2 soft deletes the given versions of the secret, which can be restored with UndeleteKvSecretV2.
+// Without versions, the version set with WithVersion is deleted, or else the latest version.
+func (c *SecretPath) DeleteKvSecretV2(ctx context.Context, versions ...uint) error {
+	kv := c.vaultClient().KVv2(c.mount)
+
+	if len(versions) == 0 && c.version == 0 {
+		if err := kv.Delete(ctx, c.path()); err != nil {
+			return fmt.Errorf("unable to delete secret: %w", err)
+		}
+		return nil
+	}
+
+	vs, err := c.kvVersions(versions)
+	if err != nil {
+		return err
+	}
+
+	if err := kv.DeleteVersions(ctx, c.path(), vs); err != nil {
+		return fmt.Errorf("unable to delete secret versions: %w", err)
+	}
+	return nil
+}
+
+// UndeleteKvSecretV2 restores the given soft deleted versions of the secret.
+// Without versions, the version set with WithVersion is restored.
+func (c *SecretPath) UndeleteKvSecretV2(ctx context.Context, versions ...uint) error {
+	vs, err := c.kvVersions(versions)
+	if err != nil {
+		return err
+	}
+
+	if err := c.vaultClient().KVv2(c.mount).Undelete(ctx, c.path(), vs); err != nil {
+		return fmt.Errorf("unable to undelete secret versions: %w", err)
+	}
+	return nil
+}
+
+// DestroyKvSecretV2 permanently removes the data of the given versions of the secret.
+// Without versions, the version set with WithVersion is destroyed.
+func (c *SecretPath) DestroyKvSecretV2(ctx context.Context, versions ...uint) error {
+	vs, err := c.kvVersions(versions)
+	if err != nil {
+		return err
+	}
+
+	if err := c.vaultClient().KVv2(c.mount).Destroy(ctx, c.path(), vs); err != nil {
+		return fmt.Errorf("unable to destroy secret versions: %w", err)
+	}
+	return nil
+}
+
+// RollbackKvSecretV2 writes the version set with WithVersion again as the newest version of the secret
+// and returns the metadata of that new version. The version must not be deleted or destroyed.
+// If the secret is written to while rolling back, ErrCheckAndSetMismatch is returned.
+func (c *SecretPath) RollbackKvSecretV2(ctx context.Context) (*hashiVault.KVVersionMetadata, error) {
+	if c.version == 0 {
+		return nil, errors.New("no version to roll back to, set one with WithVersion")
+	}
+
+	version, err := uintToInt(c.version)
+	if err != nil {
+		return nil, fmt.Errorf("incompatible version: %w", err)
+	}
+
+	secret, err := c.vaultClient().KVv2(c.mount).Rollback(ctx, c.path(), version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to roll back secret: %w", checkAndSetError(err))
+	}
+	return secret.VersionMetadata, nil
+}
+
+// kvVersions returns the given versions, or else the version set with WithVersion.
+func (c *SecretPath) kvVersions(versions []uint) ([]int, error) {
+	if len(versions) == 0 && c.version != 0 {
+		versions = []uint{c.version}
+	}
+	if len(versions) == 0 {
+		return nil, errors.New("no secret version given, pass one or set one with WithVersion")
+	}
+
+	vs := make([]int, 0, len(versions))
+	for _, v := range versions {
+		i, err := uintToInt(v)
+		if err != nil {
+			return nil, fmt.Errorf("incompatible version: %w", err)
+		}
+		vs = append(vs, i)
+	}
+
+	return vs, nil
+}
+
+// GetKvMetadataV2 returns the metadata of the secret, including its versions and custom metadata.
+func (c *SecretPath) GetKvMetadataV2(ctx context.Context) (*hashiVault.KVMetadata, error) {
+	metadata, err := c.vaultClient().KVv2(c.mount).GetMetadata(ctx, c.path())
+	if err != nil {
+		return nil, fmt.Errorf("unable to read secret metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+// GetKvVersionsV2 returns the metadata of every version of the secret, oldest first.
+func (c *SecretPath) GetKvVersionsV2(ctx context.Context) ([]hashiVault.KVVersionMetadata, error) {
+	versions, err := c.vaultClient().KVv2(c.mount).GetVersionsAsList(ctx, c.path())
+	if err != nil {
+		return nil, fmt.Errorf("unable to read secret versions: %w", err)
+	}
+	return versions, nil
+}
+
+// PutKvMetadataV2 replaces the metadata of the secret. Fields left unset are reset to their zero value.
+// It can also create a secret that has metadata but no versions yet.
+func (c *SecretPath) PutKvMetadataV2(ctx context.Context, metadata hashiVault.KVMetadataPutInput) error {
+	if err := c.vaultClient().KVv2(c.mount).PutMetadata(ctx, c.path(), metadata); err != nil {
+		return fmt.Errorf("unable to write secret metadata: %w", err)
+	}
+	return nil
+}
+
+// PatchKvMetadataV2 updates the metadata of the secret. Fields left nil are kept.
+// Custom metadata is merged, and a key set to nil is removed.
+func (c *SecretPath) PatchKvMetadataV2(ctx context.Context, metadata hashiVault.KVMetadataPatchInput) error {
+	if err := c.vaultClient().KVv2(c.mount).PatchMetadata(ctx, c.path(), metadata); err != nil {
+		return fmt.Errorf("unable to patch secret metadata: %w", err)
+	}
+	return nil
+}
+
+// kvWriteOptions returns the options of a KVv2 write to the path.
+func (c *SecretPath) kvWriteOptions() ([]hashiVault.KVOption, error) {
+	if c.checkAndSet == nil {
+		return nil, nil
+	}
+
+	cas, err := uintToInt(*c.checkAndSet)
+	if err != nil {
+		return nil, fmt.Errorf("incompatible check-and-set version: %w", err)
+	}
+
+	return []hashiVault.KVOption{hashiVault.WithCheckAndSet(cas)}, nil
+}
+
+// checkAndSetError marks the error of a write rejected because its check-and-set version did not match.
+func checkAndSetError(err error) error {
+	var respErr *hashiVault.ResponseError
+	if !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		return err
+	}
+
+	for _, msg := range respErr.Errors {
+		if strings.Contains(msg, "check-and-set parameter did not match") {
+			return fmt.Errorf("%w: %w", ErrCheckAndSetMismatch, err)
+		}
+	}
+
+	return err
+}
+
 // GetSecret retrieves a secret from the specified path.
 func (c *SecretPath) GetSecret(ctx context.Context) (*hashiVault.Secret, error) {
-	secret, err := c.client.Client().Logical().ReadWithContext(ctx, c.path())
+	secret, err := c.vaultClient().Logical().ReadWithContext(ctx, c.path())
 	if err != nil {
 		return nil, fmt.Errorf("unable to read secrets: %w", err)
 	} else if secret == nil {
@@ -68,7 +286,7 @@ func (c *SecretPath) TransitEncrypt(ctx context.Context, data string) (*hashiVau
 	plaintext := base64.StdEncoding.EncodeToString([]byte(data))
 
 	// Encrypt the data using the transit engine
-	encryptData, err := c.client.Client().Logical().WriteWithContext(ctx, c.pathWithType(pathKeyTransitEncrypt), map[string]any{
+	encryptData, err := c.vaultClient().Logical().WriteWithContext(ctx, c.pathWithType(pathKeyTransitEncrypt), map[string]any{
 		TransitKeyPlainText: plaintext,
 	})
 	if err != nil {
@@ -81,7 +299,7 @@ func (c *SecretPath) TransitEncrypt(ctx context.Context, data string) (*hashiVau
 // TransitDecrypt decrypts the given data using the transit engine.
 func (c *SecretPath) TransitDecrypt(ctx context.Context, data string) (string, error) {
 	// Decrypt the data using the transit engine
-	decryptData, err := c.client.Client().Logical().WriteWithContext(ctx, c.pathWithType(pathKeyTransitDecrypt), map[string]any{
+	decryptData, err := c.vaultClient().Logical().WriteWithContext(ctx, c.pathWithType(pathKeyTransitDecrypt), map[string]any{
 		TransitKeyCipherText: data,
 	})
 	if err != nil {
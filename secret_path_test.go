@@ -0,0 +1,393 @@
+package vaulty
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	hashiVault "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPathClient returns a client logged in to the given test Vault server with the given options.
+func newTestPathClient(t *testing.T, mux *http.ServeMux, opts ...ClientOption) Client {
+	t.Helper()
+
+	mux.HandleFunc("PUT /v1/auth/userpass/login/user", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestJSON(t, w, longLivedAuthResponse("path-token"))
+	})
+	srv := newTestVaultServer(t, mux)
+
+	vc, err := NewClient(append([]ClientOption{
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithAddr(srv.URL),
+		WithUserPassAuth("user", "pass"),
+	}, opts...)...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vc.Close(context.Background()))
+	})
+
+	return vc
+}
+
+func TestSecretPath_Namespace(t *testing.T) {
+	t.Parallel()
+
+	namespaces := make(map[string]string)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/secret/data/{name}", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "path-token", r.Header.Get("X-Vault-Token"))
+		namespaces[r.PathValue("name")] = r.Header.Get("X-Vault-Namespace")
+		writeTestJSON(t, w, map[string]any{
+			"data": map[string]any{
+				"data":     map[string]any{"key": "value"},
+				"metadata": map[string]any{"version": 1},
+			},
+		})
+	})
+	vc := newTestPathClient(t, mux, WithNamespace("org/team-a"), WithKvv2Mount("secret"))
+
+	_, err := vc.Path("own").GetKvSecretV2(t.Context())
+	require.NoError(t, err)
+	_, err = vc.Path("shared", WithPathNamespace("org")).GetKvSecretV2(t.Context())
+	require.NoError(t, err)
+	_, err = vc.Path("root", WithPathNamespace("")).GetKvSecretV2(t.Context())
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]string{
+		"own":    "org/team-a",
+		"shared": "org",
+		"root":   "",
+	}, namespaces)
+	require.Equal(t, "org/team-a", vc.Client().Namespace())
+}
+
+// kvVersionResponse is the response to a KV v2 write of the given version.
+func kvVersionResponse(version int) map[string]any {
+	return map[string]any{
+		"data": map[string]any{
+			"created_time":  "2025-01-02T03:04:05Z",
+			"deletion_time": "",
+			"destroyed":     false,
+			"version":       version,
+		},
+	}
+}
+
+// writeTestVaultError writes a Vault error response with the given status code.
+func writeTestVaultError(t *testing.T, w http.ResponseWriter, status int, msg string) {
+	t.Helper()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	require.NoError(t, json.NewEncoder(w).Encode(map[string]any{"errors": []string{msg}}))
+}
+
+func TestSecretPath_PutKvSecretV2(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		opts        []PathOption
+		wantOptions any
+	}{
+		{
+			name:        "without check-and-set",
+			wantOptions: nil,
+		},
+		{
+			name:        "create only",
+			opts:        []PathOption{WithCheckAndSet(0)},
+			wantOptions: map[string]any{"cas": float64(0)},
+		},
+		{
+			name:        "check-and-set",
+			opts:        []PathOption{WithCheckAndSet(3)},
+			wantOptions: map[string]any{"cas": float64(3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var body map[string]any
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /v1/team/data/app/config", func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				writeTestJSON(t, w, kvVersionResponse(4))
+			})
+			vc := newTestPathClient(t, mux, WithKvv2Mount("team"))
+
+			opts := append([]PathOption{WithPrefix("app")}, tt.opts...)
+			metadata, err := vc.Path("config", opts...).PutKvSecretV2(t.Context(), map[string]any{"user": "admin"})
+			require.NoError(t, err)
+
+			require.Equal(t, 4, metadata.Version)
+			require.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), metadata.CreatedTime)
+			require.Equal(t, map[string]any{"user": "admin"}, body["data"])
+			require.Equal(t, tt.wantOptions, body["options"])
+		})
+	}
+}
+
+func TestSecretPath_PatchKvSecretV2(t *testing.T) {
+	t.Parallel()
+
+	var body map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /v1/secret/data/config", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/merge-patch+json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		writeTestJSON(t, w, kvVersionResponse(5))
+	})
+	mux.HandleFunc("PATCH /v1/secret/data/missing", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestVaultError(t, w, http.StatusNotFound, "")
+	})
+	vc := newTestPathClient(t, mux, WithKvv2Mount("secret"))
+
+	metadata, err := vc.Path("config", WithCheckAndSet(4)).PatchKvSecretV2(t.Context(), map[string]any{
+		"password": "rotated",
+		"legacy":   nil,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 5, metadata.Version)
+	require.Equal(t, map[string]any{
+		"data":    map[string]any{"password": "rotated", "legacy": nil},
+		"options": map[string]any{"cas": float64(4)},
+	}, body)
+
+	_, err = vc.Path("missing").PatchKvSecretV2(t.Context(), map[string]any{"password": "rotated"})
+	require.ErrorIs(t, err, ErrSecretNotFound)
+}
+
+func TestSecretPath_CheckAndSetMismatch(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/secret/data/conflict", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestVaultError(t, w, http.StatusBadRequest, "check-and-set parameter did not match the current version")
+	})
+	mux.HandleFunc("/v1/secret/data/invalid", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestVaultError(t, w, http.StatusBadRequest, "no data provided")
+	})
+	vc := newTestPathClient(t, mux, WithKvv2Mount("secret"))
+
+	_, err := vc.Path("conflict", WithCheckAndSet(1)).PutKvSecretV2(t.Context(), map[string]any{"key": "value"})
+	require.ErrorIs(t, err, ErrCheckAndSetMismatch)
+
+	_, err = vc.Path("conflict", WithCheckAndSet(1)).PatchKvSecretV2(t.Context(), map[string]any{"key": "value"})
+	require.ErrorIs(t, err, ErrCheckAndSetMismatch)
+
+	_, err = vc.Path("invalid").PutKvSecretV2(t.Context(), map[string]any{})
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrCheckAndSetMismatch)
+}
+
+func TestSecretPath_KvMetadataV2(t *testing.T) {
+	t.Parallel()
+
+	var putBody, patchBody map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/team/metadata/app/config", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestJSON(t, w, map[string]any{
+			"data": map[string]any{
+				"cas_required":         true,
+				"created_time":         "2025-01-01T00:00:00Z",
+				"current_version":      2,
+				"custom_metadata":      map[string]any{"owner": "payments", "rotation_due": "2025-06-01"},
+				"delete_version_after": "720h0m0s",
+				"max_versions":         5,
+				"oldest_version":       1,
+				"updated_time":         "2025-02-01T00:00:00Z",
+				"versions": map[string]any{
+					"2": map[string]any{"created_time": "2025-02-01T00:00:00Z", "deletion_time": "", "destroyed": false},
+					"1": map[string]any{"created_time": "2025-01-01T00:00:00Z", "deletion_time": "2025-01-15T00:00:00Z", "destroyed": true},
+				},
+			},
+		})
+	})
+	mux.HandleFunc("PUT /v1/team/metadata/app/config", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&putBody))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("PATCH /v1/team/metadata/app/config", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/merge-patch+json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&patchBody))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	vc := newTestPathClient(t, mux, WithKvv2Mount("team"))
+	path := vc.Path("config", WithPrefix("app"))
+
+	metadata, err := path.GetKvMetadataV2(t.Context())
+	require.NoError(t, err)
+	require.True(t, metadata.CASRequired)
+	require.Equal(t, 2, metadata.CurrentVersion)
+	require.Equal(t, 5, metadata.MaxVersions)
+	require.Equal(t, 30*24*time.Hour, metadata.DeleteVersionAfter)
+	require.Equal(t, map[string]any{"owner": "payments", "rotation_due": "2025-06-01"}, metadata.CustomMetadata)
+
+	versions, err := path.GetKvVersionsV2(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, []hashiVault.KVVersionMetadata{
+		{
+			Version:      1,
+			CreatedTime:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			DeletionTime: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+			Destroyed:    true,
+		},
+		{
+			Version:     2,
+			CreatedTime: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}, versions)
+
+	require.NoError(t, path.PutKvMetadataV2(t.Context(), hashiVault.KVMetadataPutInput{
+		CASRequired:        true,
+		CustomMetadata:     map[string]any{"owner": "payments"},
+		DeleteVersionAfter: time.Hour,
+		MaxVersions:        10,
+	}))
+	require.Equal(t, map[string]any{
+		"cas_required":         true,
+		"custom_metadata":      map[string]any{"owner": "payments"},
+		"delete_version_after": "1h0m0s",
+		"max_versions":         float64(10),
+	}, putBody)
+
+	maxVersions := 3
+	require.NoError(t, path.PatchKvMetadataV2(t.Context(), hashiVault.KVMetadataPatchInput{
+		CustomMetadata: map[string]any{"rotation_due": "2025-12-01"},
+		MaxVersions:    &maxVersions,
+	}))
+	require.Equal(t, map[string]any{
+		"custom_metadata": map[string]any{"rotation_due": "2025-12-01"},
+		"max_versions":    float64(3),
+	}, patchBody)
+}
+
+func TestSecretPath_KvMetadataV2_NotFound(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/secret/metadata/missing", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		writeTestJSON(t, w, map[string]any{"errors": []string{}})
+	})
+	vc := newTestPathClient(t, mux, WithKvv2Mount("secret"))
+
+	_, err := vc.Path("missing").GetKvMetadataV2(t.Context())
+	require.ErrorIs(t, err, ErrSecretNotFound)
+
+	_, err = vc.Path("missing").GetKvVersionsV2(t.Context())
+	require.ErrorIs(t, err, ErrSecretNotFound)
+}
+
+func TestSecretPath_KvLifecycleV2(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		method string
+		path   string
+		body   map[string]any
+	}
+
+	var requests []request
+	record := func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if r.ContentLength > 0 {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		}
+		requests = append(requests, request{method: r.Method, path: r.URL.Path, body: body})
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /v1/secret/data/app/config", record)
+	mux.HandleFunc("PUT /v1/secret/delete/app/config", record)
+	mux.HandleFunc("PUT /v1/secret/undelete/app/config", record)
+	mux.HandleFunc("PUT /v1/secret/destroy/app/config", record)
+	vc := newTestPathClient(t, mux, WithKvv2Mount("secret"))
+
+	latest := vc.Path("config", WithPrefix("app"))
+	version2 := vc.Path("config", WithPrefix("app"), WithVersion(2))
+
+	require.NoError(t, latest.DeleteKvSecretV2(t.Context()))
+	require.NoError(t, version2.DeleteKvSecretV2(t.Context()))
+	require.NoError(t, latest.DeleteKvSecretV2(t.Context(), 1, 3))
+	require.NoError(t, version2.UndeleteKvSecretV2(t.Context()))
+	require.NoError(t, latest.DestroyKvSecretV2(t.Context(), 1))
+
+	require.Equal(t, []request{
+		{method: http.MethodDelete, path: "/v1/secret/data/app/config"},
+		{method: http.MethodPut, path: "/v1/secret/delete/app/config", body: map[string]any{"versions": []any{"2"}}},
+		{method: http.MethodPut, path: "/v1/secret/delete/app/config", body: map[string]any{"versions": []any{"1", "3"}}},
+		{method: http.MethodPut, path: "/v1/secret/undelete/app/config", body: map[string]any{"versions": []any{float64(2)}}},
+		{method: http.MethodPut, path: "/v1/secret/destroy/app/config", body: map[string]any{"versions": []any{float64(1)}}},
+	}, requests)
+
+	require.ErrorContains(t, latest.UndeleteKvSecretV2(t.Context()), "no secret version given")
+	require.ErrorContains(t, latest.DestroyKvSecretV2(t.Context()), "no secret version given")
+	require.Len(t, requests, 5)
+}
+
+func TestSecretPath_RollbackKvSecretV2(t *testing.T) {
+	t.Parallel()
+
+	kvData := func(version int, data map[string]any, deletionTime string) map[string]any {
+		return map[string]any{
+			"data": map[string]any{
+				"data": data,
+				"metadata": map[string]any{
+					"created_time":  "2025-01-01T00:00:00Z",
+					"deletion_time": deletionTime,
+					"destroyed":     false,
+					"version":       version,
+				},
+			},
+		}
+	}
+
+	var written map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/secret/data/{name}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("version") {
+		case "":
+			writeTestJSON(t, w, kvData(3, map[string]any{"password": "bad"}, ""))
+		case "1":
+			writeTestJSON(t, w, kvData(1, map[string]any{"password": "good"}, ""))
+		case "2":
+			writeTestJSON(t, w, kvData(2, map[string]any{"password": "deleted"}, "2025-01-02T00:00:00Z"))
+		}
+	})
+	mux.HandleFunc("PUT /v1/secret/data/config", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&written))
+		writeTestJSON(t, w, kvVersionResponse(4))
+	})
+	mux.HandleFunc("PUT /v1/secret/data/racing", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestVaultError(t, w, http.StatusBadRequest, "check-and-set parameter did not match the current version")
+	})
+	vc := newTestPathClient(t, mux, WithKvv2Mount("secret"))
+
+	metadata, err := vc.Path("config", WithVersion(1)).RollbackKvSecretV2(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, 4, metadata.Version)
+	require.Equal(t, map[string]any{
+		"data":    map[string]any{"password": "good"},
+		"options": map[string]any{"cas": float64(3)},
+	}, written)
+
+	_, err = vc.Path("config", WithVersion(2)).RollbackKvSecretV2(t.Context())
+	require.ErrorContains(t, err, "cannot roll back to a version that has been deleted")
+
+	_, err = vc.Path("racing", WithVersion(1)).RollbackKvSecretV2(t.Context())
+	require.ErrorIs(t, err, ErrCheckAndSetMismatch)
+
+	_, err = vc.Path("config").RollbackKvSecretV2(t.Context())
+	require.ErrorContains(t, err, "no version to roll back to")
+}
@@ -0,0 +1,276 @@
+package vaulty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ConfigChange describes a secret whose new version was merged into viper by a ViperSource.
+type ConfigChange struct {
+	// Path is the path of the secret, relative to its mount.
+	Path string
+
+	// Version is the version of the secret that was merged, or 0 if the secret no longer exists.
+	Version int
+}
+
+// ViperSource merges the configuration held by a KV v2 secret, or by every secret below a KV v2 folder,
+// into a viper instance and keeps it up to date as new versions of the secrets are written.
+//
+// The keys of a secret are merged at the root of the viper instance, or under the key set by WithViperSourceKey.
+// Below a folder, every secret is merged under its path relative to the folder, so the secret apps/config/flags
+// of the folder apps/config/ is read with vip.GetBool("flags.new_checkout").
+// Viper can only merge configuration, so keys removed from a secret keep their last value.
+type ViperSource interface {
+	// Load merges the latest version of the configuration into the viper instance.
+	Load(ctx context.Context) error
+
+	// Watch checks for new versions of the configuration until the context is done, merging them into the
+	// viper instance and then calling the OnConfigChange functions. A failed check is logged and retried.
+	Watch(ctx context.Context) error
+
+	// OnConfigChange adds a function that is called for every secret whose new version was merged by Watch.
+	OnConfigChange(fn func(change ConfigChange))
+}
+
+// viperSource is a struct that implements the ViperSource interface.
+type viperSource struct {
+	client Client
+	vip    *viper.Viper
+	path   string
+	opts   *viperSourceOptions
+
+	// mu serializes the merges, and guards the versions merged so far and the change functions.
+	mu       sync.Mutex
+	versions map[string]int
+	onChange []func(change ConfigChange)
+}
+
+// NewViperSource creates a new ViperSource that merges the secret at the given path, or every secret below it
+// when the path ends with a slash, into the viper instance.
+//
+// Viper is not safe for concurrent use, so when Watch runs in the background the configuration should be read
+// from the OnConfigChange functions, or reads should be guarded by the application.
+func NewViperSource(client Client, vip *viper.Viper, path string, opts ...ViperSourceOption) (ViperSource, error) {
+	switch {
+	case client == nil:
+		return nil, errors.New("client is nil")
+	case vip == nil:
+		return nil, errors.New("no viper configuration provided")
+	case strings.Trim(path, "/") == "":
+		return nil, errors.New("path is empty")
+	}
+
+	o := newViperSourceOptions(opts...)
+	if o.pollInterval <= 0 {
+		return nil, errors.New("poll interval must be positive")
+	}
+
+	return &viperSource{
+		client:   client,
+		vip:      vip,
+		path:     strings.TrimPrefix(path, "/"),
+		opts:     o,
+		versions: make(map[string]int),
+	}, nil
+}
+
+// Load merges the latest version of the configuration into the viper instance.
+func (s *viperSource) Load(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.sync(ctx)
+	return err
+}
+
+// Watch checks for new versions of the configuration every poll interval until the context is done.
+func (s *viperSource) Watch(ctx context.Context) error {
+	ticker := time.NewTicker(s.opts.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.poll(ctx)
+		}
+	}
+}
+
+// OnConfigChange adds a function that is called for every secret whose new version was merged by Watch.
+func (s *viperSource) OnConfigChange(fn func(change ConfigChange)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onChange = append(s.onChange, fn)
+}
+
+// poll merges the new versions of the configuration and calls the change functions for them.
+// The functions are called without holding the lock, so they may call Load or OnConfigChange.
+func (s *viperSource) poll(ctx context.Context) {
+	s.mu.Lock()
+	changes, err := s.sync(ctx)
+	onChange := slices.Clone(s.onChange)
+	s.mu.Unlock()
+
+	if err != nil {
+		if ctx.Err() == nil {
+			s.opts.l.Warn("unable to check for configuration changes",
+				slog.String(loggingKeySecretName, s.path),
+				slog.String(loggingKeyError, err.Error()),
+			)
+		}
+		return
+	}
+
+	for _, change := range changes {
+		for _, fn := range onChange {
+			fn(change)
+		}
+	}
+}
+
+// sync merges the secrets whose version changed since the last sync and returns the changes.
+func (s *viperSource) sync(ctx context.Context) ([]ConfigChange, error) {
+	versions, err := s.currentVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []ConfigChange
+	merged := make(map[string]any)
+	for _, path := range slices.Sorted(maps.Keys(versions)) {
+		version := versions[path]
+		if s.versions[path] == version {
+			continue
+		}
+
+		secret, err := s.repository(path, WithVersion(uint(version))).GetKvSecretV2(ctx) // nolint:gosec // Versions are positive
+		switch {
+		case errors.Is(err, ErrSecretNotFound):
+			// The version was deleted before it could be read, so there is nothing to merge.
+		case err != nil:
+			return nil, fmt.Errorf("unable to read %s: %w", path, err)
+		default:
+			mergeNested(merged, s.keys(path), secret.Data)
+		}
+		changes = append(changes, ConfigChange{Path: path, Version: version})
+	}
+
+	for path := range s.versions {
+		if _, ok := versions[path]; !ok {
+			changes = append(changes, ConfigChange{Path: path, Version: 0})
+		}
+	}
+
+	if len(merged) > 0 {
+		if err := s.vip.MergeConfigMap(merged); err != nil {
+			return nil, fmt.Errorf("unable to merge configuration: %w", err)
+		}
+	}
+
+	s.versions = versions
+	return changes, nil
+}
+
+// currentVersions returns the current version of every secret of the configuration, keyed by path.
+func (s *viperSource) currentVersions(ctx context.Context) (map[string]int, error) {
+	paths := []string{s.path}
+	if s.isFolder() {
+		var err error
+		paths, err = s.secretPaths(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	type result struct {
+		version int
+		err     error
+	}
+	results := resolveConcurrently(paths, s.opts.concurrency, func(path string) result {
+		metadata, err := s.repository(path).GetKvMetadataV2(ctx)
+		if err != nil {
+			return result{err: fmt.Errorf("unable to check %s: %w", path, err)}
+		}
+		return result{version: metadata.CurrentVersion}
+	})
+
+	versions := make(map[string]int, len(results))
+	for path, res := range results {
+		if res.err != nil {
+			return nil, res.err
+		} else if res.version > 0 {
+			versions[path] = res.version
+		}
+	}
+	return versions, nil
+}
+
+// secretPaths returns the paths of the secrets below the folder.
+func (s *viperSource) secretPaths(ctx context.Context) ([]string, error) {
+	var paths []string
+	err := s.repository(s.path).Walk(ctx, func(path string, err error) error {
+		if err != nil {
+			return err
+		} else if !strings.HasSuffix(path, "/") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list %s: %w", s.path, err)
+	}
+	return paths, nil
+}
+
+// repository returns the secret path for the given path on the configured mount.
+func (s *viperSource) repository(path string, opts ...PathOption) Repository {
+	if s.opts.mount != "" {
+		opts = append(opts, WithMount(s.opts.mount))
+	}
+	return s.client.Path(path, opts...)
+}
+
+// isFolder reports whether the configuration is every secret below a folder.
+func (s *viperSource) isFolder() bool {
+	return strings.HasSuffix(s.path, "/")
+}
+
+// keys returns the viper keys the secret at the given path is merged under.
+func (s *viperSource) keys(path string) []string {
+	var keys []string
+	if s.opts.key != "" {
+		keys = strings.Split(s.opts.key, ".")
+	}
+
+	if s.isFolder() {
+		keys = append(keys, strings.Split(strings.TrimPrefix(path, s.path), "/")...)
+	}
+	return keys
+}
+
+// mergeNested merges the data into the map under the nested keys.
+func mergeNested(m map[string]any, keys []string, data map[string]any) {
+	for _, key := range keys {
+		child, ok := m[key].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			m[key] = child
+		}
+		m = child
+	}
+
+	maps.Copy(m, data)
+}
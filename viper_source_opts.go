@@ -0,0 +1,73 @@
+package vaulty
+
+import (
+	"log/slog"
+	"time"
+)
+
+// ViperSourceOption is a function that configures a ViperSource.
+type ViperSourceOption func(o *viperSourceOptions)
+
+// viperSourceOptions holds the settings of a ViperSource.
+type viperSourceOptions struct {
+	mount        string
+	key          string
+	pollInterval time.Duration
+	concurrency  int
+	l            *slog.Logger
+}
+
+// newViperSourceOptions returns the viper source options with the options applied.
+func newViperSourceOptions(opts ...ViperSourceOption) *viperSourceOptions {
+	o := &viperSourceOptions{
+		mount:        "",
+		key:          "",
+		pollInterval: defaultViperSourcePollInterval,
+		concurrency:  defaultViperSourceConcurrency,
+		l:            slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	o.concurrency = max(o.concurrency, 1)
+
+	return o
+}
+
+// WithViperSourceMount sets the KV v2 mount the configuration is read from. Defaults to the mount of the client.
+func WithViperSourceMount(mount string) ViperSourceOption {
+	return func(o *viperSourceOptions) {
+		o.mount = mount
+	}
+}
+
+// WithViperSourceKey merges the configuration under the given viper key, such as "features", instead of at the root.
+func WithViperSourceKey(key string) ViperSourceOption {
+	return func(o *viperSourceOptions) {
+		o.key = key
+	}
+}
+
+// WithViperSourcePollInterval sets how often Watch checks for new versions. Defaults to 30 seconds.
+func WithViperSourcePollInterval(interval time.Duration) ViperSourceOption {
+	return func(o *viperSourceOptions) {
+		o.pollInterval = interval
+	}
+}
+
+// WithViperSourceConcurrency sets how many secrets of a folder are checked for new versions at the same time.
+// Defaults to 8.
+func WithViperSourceConcurrency(n int) ViperSourceOption {
+	return func(o *viperSourceOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithViperSourceLogger sets the logger Watch reports failed polls to. Defaults to slog.Default().
+func WithViperSourceLogger(l *slog.Logger) ViperSourceOption {
+	return func(o *viperSourceOptions) {
+		o.l = l
+	}
+}
@@ -0,0 +1,279 @@
+package vaulty
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// testKvStore is an in-memory KV v2 mount named "config", keeping every version of its secrets.
+type testKvStore struct {
+	mu      sync.Mutex
+	secrets map[string][]map[string]any
+}
+
+// put writes the data as a new version of the secret at the given path.
+func (s *testKvStore) put(path string, data map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.secrets[path] = append(s.secrets[path], data)
+}
+
+// newTestViperSourceClient returns a client for the store.
+func newTestViperSourceClient(t *testing.T, store *testKvStore) Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/config/metadata/{path...}", func(w http.ResponseWriter, r *http.Request) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+
+		path := r.PathValue("path")
+		if r.URL.Query().Get("list") != "true" {
+			versions, ok := store.secrets[path]
+			if !ok {
+				writeTestVaultError(t, w, http.StatusNotFound, "")
+				return
+			}
+			writeTestJSON(t, w, map[string]any{"data": map[string]any{"current_version": len(versions)}})
+			return
+		}
+
+		var keys []string
+		for name := range store.secrets {
+			rest, ok := strings.CutPrefix(name, strings.TrimSuffix(path, "/")+"/")
+			if !ok {
+				continue
+			}
+			if folder, _, nested := strings.Cut(rest, "/"); nested {
+				rest = folder + "/"
+			}
+			if !slices.Contains(keys, rest) {
+				keys = append(keys, rest)
+			}
+		}
+		if len(keys) == 0 {
+			writeTestVaultError(t, w, http.StatusNotFound, "")
+			return
+		}
+		writeTestJSON(t, w, map[string]any{"data": map[string]any{"keys": keys}})
+	})
+	mux.HandleFunc("GET /v1/config/data/{path...}", func(w http.ResponseWriter, r *http.Request) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+
+		versions := store.secrets[r.PathValue("path")]
+		version, err := strconv.Atoi(r.URL.Query().Get("version"))
+		require.NoError(t, err)
+		writeTestJSON(t, w, map[string]any{
+			"data": map[string]any{
+				"data":     versions[version-1],
+				"metadata": kvVersionResponse(version)["data"],
+			},
+		})
+	})
+
+	return newTestPathClient(t, mux)
+}
+
+func TestViperSource_Secret(t *testing.T) {
+	t.Parallel()
+
+	store := &testKvStore{secrets: map[string][]map[string]any{
+		"apps/payments": {{"timeout": "5s", "features": map[string]any{"new_checkout": false}}},
+	}}
+	vc := newTestViperSourceClient(t, store)
+
+	vip := viper.New()
+	vip.Set("port", 8080)
+	src, err := NewViperSource(vc, vip, "apps/payments",
+		WithViperSourceMount("config"),
+		WithViperSourceKey("payments"),
+		WithViperSourcePollInterval(10*time.Millisecond),
+		WithViperSourceLogger(slog.New(slog.DiscardHandler)),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, src.Load(context.Background()))
+	require.Equal(t, 5*time.Second, vip.GetDuration("payments.timeout"))
+	require.False(t, vip.GetBool("payments.features.new_checkout"))
+	require.Equal(t, 8080, vip.GetInt("port"))
+
+	changes := make(chan ConfigChange, 1)
+	src.OnConfigChange(func(change ConfigChange) {
+		require.True(t, vip.GetBool("payments.features.new_checkout"))
+		changes <- change
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- src.Watch(ctx)
+	}()
+
+	store.put("apps/payments", map[string]any{"timeout": "5s", "features": map[string]any{"new_checkout": true}})
+
+	select {
+	case change := <-changes:
+		require.Equal(t, ConfigChange{Path: "apps/payments", Version: 2}, change)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no configuration change was reported")
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestViperSource_Folder(t *testing.T) {
+	t.Parallel()
+
+	store := &testKvStore{secrets: map[string][]map[string]any{
+		"apps/flags":        {{"new_checkout": true}},
+		"apps/limits/api":   {{"rps": 100}},
+		"apps/limits/batch": {{"rps": 10}},
+		"other/secret":      {{"ignored": true}},
+	}}
+	vc := newTestViperSourceClient(t, store)
+
+	vip := viper.New()
+	src, err := NewViperSource(vc, vip, "apps/", WithViperSourceMount("config"))
+	require.NoError(t, err)
+
+	require.NoError(t, src.Load(context.Background()))
+	require.True(t, vip.GetBool("flags.new_checkout"))
+	require.Equal(t, 100, vip.GetInt("limits.api.rps"))
+	require.Equal(t, 10, vip.GetInt("limits.batch.rps"))
+	require.False(t, vip.IsSet("ignored"))
+
+	var changes []ConfigChange
+	src.OnConfigChange(func(change ConfigChange) {
+		changes = append(changes, change)
+	})
+
+	store.put("apps/limits/batch", map[string]any{"rps": 20})
+	store.put("apps/limits/stream", map[string]any{"rps": 5})
+
+	vs, ok := src.(*viperSource)
+	require.True(t, ok)
+	vs.poll(context.Background())
+
+	require.Equal(t, []ConfigChange{
+		{Path: "apps/limits/batch", Version: 2},
+		{Path: "apps/limits/stream", Version: 1},
+	}, changes)
+	require.Equal(t, 100, vip.GetInt("limits.api.rps"))
+	require.Equal(t, 20, vip.GetInt("limits.batch.rps"))
+	require.Equal(t, 5, vip.GetInt("limits.stream.rps"))
+}
+
+func TestViperSource_Concurrency(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, maxInFlight atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/config/metadata/apps/", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestJSON(t, w, map[string]any{"data": map[string]any{"keys": []string{"a", "b", "c", "d"}}})
+	})
+	mux.HandleFunc("GET /v1/config/metadata/apps/{name}", func(w http.ResponseWriter, _ *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for current := maxInFlight.Load(); n > current && !maxInFlight.CompareAndSwap(current, n); {
+			current = maxInFlight.Load()
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		writeTestJSON(t, w, map[string]any{"data": map[string]any{"current_version": 1}})
+	})
+	mux.HandleFunc("GET /v1/config/data/apps/{name}", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(t, w, map[string]any{
+			"data": map[string]any{
+				"data":     map[string]any{"name": r.PathValue("name")},
+				"metadata": kvVersionResponse(1)["data"],
+			},
+		})
+	})
+
+	vip := viper.New()
+	src, err := NewViperSource(newTestPathClient(t, mux), vip, "apps/",
+		WithViperSourceMount("config"),
+		WithViperSourceConcurrency(1),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, src.Load(context.Background()))
+	require.Equal(t, "d", vip.GetString("d.name"))
+	require.Equal(t, int32(1), maxInFlight.Load())
+
+	require.Equal(t, 1, newViperSourceOptions(WithViperSourceConcurrency(0)).concurrency)
+}
+
+func TestViperSource_OnConfigChange_CallsSource(t *testing.T) {
+	t.Parallel()
+
+	store := &testKvStore{secrets: map[string][]map[string]any{
+		"apps/payments": {{"timeout": "5s"}},
+	}}
+	vc := newTestViperSourceClient(t, store)
+
+	vip := viper.New()
+	src, err := NewViperSource(vc, vip, "apps/payments", WithViperSourceMount("config"))
+	require.NoError(t, err)
+	require.NoError(t, src.Load(context.Background()))
+
+	// A change function may reload the source and add further change functions.
+	src.OnConfigChange(func(ConfigChange) {
+		require.NoError(t, src.Load(context.Background()))
+		src.OnConfigChange(func(ConfigChange) {})
+	})
+
+	store.put("apps/payments", map[string]any{"timeout": "10s"})
+
+	vs, ok := src.(*viperSource)
+	require.True(t, ok)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		vs.poll(context.Background())
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the change function deadlocked the poll")
+	}
+	require.Equal(t, 10*time.Second, vip.GetDuration("timeout"))
+}
+
+func TestNewViperSource_Invalid(t *testing.T) {
+	t.Parallel()
+
+	vc := newTestViperSourceClient(t, &testKvStore{secrets: map[string][]map[string]any{}})
+
+	_, err := NewViperSource(nil, viper.New(), "apps/payments")
+	require.EqualError(t, err, "client is nil")
+
+	_, err = NewViperSource(vc, nil, "apps/payments")
+	require.EqualError(t, err, "no viper configuration provided")
+
+	_, err = NewViperSource(vc, viper.New(), "/")
+	require.EqualError(t, err, "path is empty")
+
+	_, err = NewViperSource(vc, viper.New(), "apps/payments", WithViperSourcePollInterval(0))
+	require.EqualError(t, err, "poll interval must be positive")
+
+	src, err := NewViperSource(vc, viper.New(), "apps/missing", WithViperSourceMount("config"))
+	require.NoError(t, err)
+	require.ErrorContains(t, src.Load(context.Background()), "unable to check apps/missing")
+}
@@ -0,0 +1,200 @@
+package vaulty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	hashiVault "github.com/hashicorp/vault/api"
+)
+
+var (
+	// SkipSubtree is returned by a WalkFunc to skip the folder it was called for.
+	SkipSubtree = errors.New("skip this subtree")
+
+	// ErrPermissionDenied is returned when the client token is not allowed to access a path.
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// WalkFunc is called by Walk for every secret and folder below the walked path.
+//
+// The path is relative to the KV mount, so it can be passed to Client.Path as is, and folders end with a slash.
+// A folder is visited before it is listed; returning SkipSubtree skips its contents.
+// When a folder cannot be listed, the function is called again for it with the error, which wraps
+// ErrPermissionDenied if the client token may not list it. This includes the walked folder itself, which is
+// otherwise not visited. Returning nil then carries on with the rest of the walk.
+// Any other error stops the walk and is returned by Walk.
+//
+// Folders are listed concurrently, but the function is never called concurrently.
+type WalkFunc func(path string, err error) error
+
+// List returns the names of the secrets and folders directly below the KV v2 path. Folder names end with a slash.
+func (c *SecretPath) List(ctx context.Context) ([]string, error) {
+	return listKvV2(ctx, c.vaultClient(), c.mount, c.path())
+}
+
+// Walk calls fn for every secret and folder below the KV v2 path, listing folders with bounded concurrency.
+func (c *SecretPath) Walk(ctx context.Context, fn WalkFunc, opts ...WalkOption) error {
+	o := newWalkOptions(opts...)
+	vc := c.vaultClient()
+
+	root := c.path()
+	if root != "" && !strings.HasSuffix(root, "/") {
+		root += "/"
+	}
+
+	walkCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w := &walker{
+		ctx:    walkCtx,
+		cancel: cancel,
+		vc:     vc,
+		mount:  c.mount,
+		fn:     fn,
+		opts:   o,
+		sem:    make(chan struct{}, o.concurrency),
+	}
+
+	w.wg.Add(1)
+	w.walk(root, 1)
+	w.wg.Wait()
+
+	if w.err != nil {
+		return w.err
+	}
+	return ctx.Err()
+}
+
+// walker holds the state of one Walk.
+type walker struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	vc     *hashiVault.Client
+	mount  string
+	fn     WalkFunc
+	opts   *walkOptions
+	sem    chan struct{}
+	wg     sync.WaitGroup
+
+	// fnMu serializes the calls to fn.
+	fnMu sync.Mutex
+
+	errOnce sync.Once
+	err     error
+}
+
+// visit calls fn for the keys of the given folder and walks the subfolders at the given depth.
+func (w *walker) visit(folder string, keys []string, depth int) {
+	defer w.wg.Done()
+
+	for _, key := range keys {
+		path := folder + key
+		if err := w.call(path, nil); errors.Is(err, SkipSubtree) {
+			continue
+		} else if err != nil {
+			w.fail(err)
+			return
+		}
+
+		if !strings.HasSuffix(key, "/") || (w.opts.maxDepth > 0 && depth >= w.opts.maxDepth) {
+			continue
+		}
+
+		w.wg.Add(1)
+		go w.walk(path, depth+1)
+	}
+}
+
+// walk lists the given folder and visits its keys.
+func (w *walker) walk(folder string, depth int) {
+	select {
+	case w.sem <- struct{}{}:
+	case <-w.ctx.Done():
+		w.wg.Done()
+		return
+	}
+
+	keys, err := listKvV2(w.ctx, w.vc, w.mount, folder)
+	<-w.sem
+
+	if err != nil {
+		if w.ctx.Err() != nil {
+			w.wg.Done()
+			return
+		}
+
+		if err := w.call(folder, err); err != nil && !errors.Is(err, SkipSubtree) {
+			w.fail(err)
+		}
+		w.wg.Done()
+		return
+	}
+
+	w.visit(folder, keys, depth)
+}
+
+// call calls fn, unless the walk has been stopped.
+func (w *walker) call(path string, err error) error {
+	w.fnMu.Lock()
+	defer w.fnMu.Unlock()
+
+	if ctxErr := w.ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	return w.fn(path, err)
+}
+
+// fail stops the walk with the given error, keeping the first one.
+func (w *walker) fail(err error) {
+	w.errOnce.Do(func() {
+		w.err = err
+		w.cancel()
+	})
+}
+
+// listKvV2 lists the keys below the given path of a KV v2 mount.
+func listKvV2(ctx context.Context, vc *hashiVault.Client, mount, path string) ([]string, error) {
+	return listKeysAt(ctx, vc, fmt.Sprintf("%s/metadata/%s", mount, path), path)
+}
+
+// listKeysAt lists the keys below the given API path, naming the error after the given path.
+func listKeysAt(ctx context.Context, vc *hashiVault.Client, apiPath, path string) ([]string, error) {
+	secret, err := vc.Logical().ListWithContext(ctx, apiPath)
+	if err != nil {
+		var respErr *hashiVault.ResponseError
+		if errors.As(err, &respErr) && respErr.StatusCode == http.StatusForbidden {
+			return nil, fmt.Errorf("unable to list %s: %w: %w", path, ErrPermissionDenied, err)
+		}
+		return nil, fmt.Errorf("unable to list %s: %w", path, err)
+	}
+
+	return listKeys(secret)
+}
+
+// listKeys returns the keys of a list response.
+func listKeys(secret *hashiVault.Secret) ([]string, error) {
+	if secret == nil || secret.Data == nil {
+		return nil, ErrSecretNotFound
+	}
+
+	raw, ok := secret.Data["keys"].([]any)
+	if !ok {
+		return nil, errors.New("unable to read keys from list response")
+	}
+
+	keys := make([]string, 0, len(raw))
+	for _, k := range raw {
+		key, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected key %v in list response", k)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,41 @@
+package vaulty
+
+// WalkOption is a function that configures a Walk.
+type WalkOption func(o *walkOptions)
+
+// walkOptions holds the settings of a Walk.
+type walkOptions struct {
+	concurrency int
+	maxDepth    int
+}
+
+// newWalkOptions returns the walk options with the options applied.
+func newWalkOptions(opts ...WalkOption) *walkOptions {
+	o := &walkOptions{
+		concurrency: defaultWalkConcurrency,
+		maxDepth:    0,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	o.concurrency = max(o.concurrency, 1)
+
+	return o
+}
+
+// WithWalkConcurrency sets how many folders are listed at the same time. Defaults to 8.
+func WithWalkConcurrency(n int) WalkOption {
+	return func(o *walkOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithMaxDepth limits how deep the walk goes below the walked path. A depth of 1 only visits its direct children.
+// Folders at the maximum depth are visited but not listed. Zero, the default, means no limit.
+func WithMaxDepth(depth int) WalkOption {
+	return func(o *walkOptions) {
+		o.maxDepth = depth
+	}
+}
@@ -0,0 +1,184 @@
+package vaulty
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestWalkClient returns a client whose KV v2 mount holds the given folders, keyed by their path without a
+// trailing slash. Folders in denied cannot be listed.
+func newTestWalkClient(t *testing.T, tree map[string][]string, denied ...string) Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/secret/metadata/{path...}", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "true", r.URL.Query().Get("list"))
+
+		folder := strings.TrimSuffix(r.PathValue("path"), "/")
+		if slices.Contains(denied, folder) {
+			writeTestVaultError(t, w, http.StatusForbidden, "permission denied")
+			return
+		}
+
+		keys, ok := tree[folder]
+		if !ok {
+			writeTestVaultError(t, w, http.StatusNotFound, "")
+			return
+		}
+		writeTestJSON(t, w, map[string]any{"data": map[string]any{"keys": keys}})
+	})
+
+	return newTestPathClient(t, mux, WithKvv2Mount("secret"))
+}
+
+var testWalkTree = map[string][]string{
+	"":               {"app/", "root-secret"},
+	"app":            {"api/", "db", "web/"},
+	"app/api":        {"key", "nested/"},
+	"app/api/nested": {"deep"},
+	"app/web":        {"cert"},
+}
+
+func TestSecretPath_List(t *testing.T) {
+	t.Parallel()
+
+	vc := newTestWalkClient(t, testWalkTree)
+
+	keys, err := vc.Path("app").List(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"api/", "db", "web/"}, keys)
+
+	_, err = vc.Path("missing").List(context.Background())
+	require.ErrorIs(t, err, ErrSecretNotFound)
+}
+
+func TestSecretPath_Walk(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		path   string
+		opts   []WalkOption
+		skip   string
+		want   []string
+		denied []string
+	}{
+		{
+			name: "subtree",
+			path: "app",
+			want: []string{
+				"app/api/", "app/api/key", "app/api/nested/", "app/api/nested/deep",
+				"app/db", "app/web/", "app/web/cert",
+			},
+		},
+		{
+			name: "mount root",
+			path: "",
+			opts: []WalkOption{WithWalkConcurrency(1)},
+			want: []string{
+				"app/", "app/api/", "app/api/key", "app/api/nested/", "app/api/nested/deep",
+				"app/db", "app/web/", "app/web/cert", "root-secret",
+			},
+		},
+		{
+			name: "skip subtree",
+			path: "app",
+			skip: "app/api/",
+			want: []string{"app/api/", "app/db", "app/web/", "app/web/cert"},
+		},
+		{
+			name: "max depth",
+			path: "app",
+			opts: []WalkOption{WithMaxDepth(2)},
+			want: []string{"app/api/", "app/api/key", "app/api/nested/", "app/db", "app/web/", "app/web/cert"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			vc := newTestWalkClient(t, testWalkTree, tt.denied...)
+
+			var got []string
+			err := vc.Path(tt.path).Walk(context.Background(), func(path string, err error) error {
+				require.NoError(t, err)
+				got = append(got, path)
+				if path == tt.skip {
+					return SkipSubtree
+				}
+				return nil
+			}, tt.opts...)
+			require.NoError(t, err)
+
+			slices.Sort(got)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSecretPath_Walk_PermissionDenied(t *testing.T) {
+	t.Parallel()
+
+	vc := newTestWalkClient(t, testWalkTree, "app/api")
+
+	var (
+		got    []string
+		failed []string
+	)
+	err := vc.Path("app").Walk(context.Background(), func(path string, err error) error {
+		if err != nil {
+			require.ErrorIs(t, err, ErrPermissionDenied)
+			failed = append(failed, path)
+			return nil
+		}
+		got = append(got, path)
+		return nil
+	})
+	require.NoError(t, err)
+
+	slices.Sort(got)
+	require.Equal(t, []string{"app/api/", "app/db", "app/web/", "app/web/cert"}, got)
+	require.Equal(t, []string{"app/api/"}, failed)
+}
+
+func TestSecretPath_Walk_Errors(t *testing.T) {
+	t.Parallel()
+
+	vc := newTestWalkClient(t, testWalkTree, "denied")
+
+	errStop := errors.New("stop")
+	err := vc.Path("app").Walk(context.Background(), func(path string, _ error) error {
+		if path == "app/db" {
+			return errStop
+		}
+		return nil
+	}, WithWalkConcurrency(1))
+	require.ErrorIs(t, err, errStop)
+
+	// The walked folder is passed to fn when it cannot be listed.
+	var failed []string
+	err = vc.Path("denied").Walk(context.Background(), func(path string, err error) error {
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		failed = append(failed, path)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"denied/"}, failed)
+
+	err = vc.Path("denied").Walk(context.Background(), func(_ string, err error) error {
+		return err
+	})
+	require.ErrorIs(t, err, ErrPermissionDenied)
+
+	err = vc.Path("missing").Walk(context.Background(), func(_ string, err error) error {
+		return err
+	})
+	require.ErrorIs(t, err, ErrSecretNotFound)
+}
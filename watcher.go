@@ -23,7 +23,13 @@ func monitorWatcher(ctx context.Context, l *slog.Logger, token string, watcher *
 			// return value of the channel to see if renewal was successful.
 		case err := <-watcher.DoneCh():
 			// Leases created by a token get revoked when the token is revoked.
-			return expiring, err
+			if err != nil {
+				l.Warn("renewal failed, secret will be re-read",
+					slog.String(loggingKeySecretName, token),
+					slog.String(loggingKeyError, err.Error()),
+				)
+			}
+			return expiring, nil
 
 			// RenewCh is a channel that receives a message when a successful
 			// renewal takes place and includes metadata about the renewal.
@@ -38,7 +44,8 @@ func monitorWatcher(ctx context.Context, l *slog.Logger, token string, watcher *
 }
 
 // handleWatcherResult processes the result of the watcher and executes
-func handleWatcherResult(l *slog.Logger, result renewResult, onExpire ...func()) error {
+// the onExpire functions when the secret is expiring, returning the first error.
+func handleWatcherResult(l *slog.Logger, result renewResult, onExpire ...func() error) error {
 	switch {
 	case result&exitRequested != 0:
 		l.Debug("result is exitRequested", slog.Int(loggingKeyResult, int(result)))
@@ -49,7 +56,9 @@ func handleWatcherResult(l *slog.Logger, result renewResult, onExpire ...func())
 			return errors.New("no onExpire functions provided")
 		}
 		for _, f := range onExpire {
-			f()
+			if err := f(); err != nil {
+				return err
+			}
 		}
 		return nil
 	default:
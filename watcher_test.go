@@ -0,0 +1,50 @@
+package vaulty
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleWatcherResult(t *testing.T) {
+	t.Parallel()
+
+	l := slog.New(slog.DiscardHandler)
+
+	t.Run("exit requested does not call onExpire", func(t *testing.T) {
+		t.Parallel()
+
+		called := false
+		err := handleWatcherResult(l, exitRequested, func() error {
+			called = true
+			return nil
+		})
+		require.NoError(t, err)
+		require.False(t, called)
+	})
+
+	t.Run("expiring without onExpire", func(t *testing.T) {
+		t.Parallel()
+
+		err := handleWatcherResult(l, expiring)
+		require.EqualError(t, err, "no onExpire functions provided")
+	})
+
+	t.Run("expiring returns the onExpire error", func(t *testing.T) {
+		t.Parallel()
+
+		wantErr := errors.New("login failed")
+		calls := 0
+		err := handleWatcherResult(l, expiring, func() error {
+			calls++
+			return wantErr
+		}, func() error {
+			calls++
+			return nil
+		})
+		require.ErrorIs(t, err, wantErr)
+		require.Equal(t, 1, calls)
+	})
+}